@@ -0,0 +1,40 @@
+package main
+
+import "bytes"
+
+// wrapAbort 开启 emit_abort_all 时将调用包裹为 trackAbort((signal) => call)，call 中以 signal 作为请求选项
+func wrapAbort(data ServiceInfo, call string) string {
+	if !data.EmitAbortAll {
+		return call
+	}
+	return "trackAbort((signal) => " + call + ")"
+}
+
+// writeAbortAll 生成模块级的 AbortController 登记与导出的 abortAll()：每次调用创建一个 AbortController 并登记，
+// 请求结束后移除；abortAll() 取消该模块所有进行中的请求（如路由切换时清理）
+// typed 为 true 时生成 TS 类型标注，indent 为每层缩进
+func writeAbortAll(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if !data.EmitAbortAll {
+		return
+	}
+	if typed {
+		buf.WriteString("const pendingControllers = new Set<AbortController>();\n\n")
+		buf.WriteString("const trackAbort = <T>(call: (signal: AbortSignal) => Promise<T>): Promise<T> => {\n")
+	} else {
+		buf.WriteString("const pendingControllers = new Set();\n\n")
+		buf.WriteString("const trackAbort = (call) => {\n")
+	}
+	buf.WriteString(indent + "const controller = new AbortController();\n")
+	buf.WriteString(indent + "pendingControllers.add(controller);\n")
+	buf.WriteString(indent + "return call(controller.signal).finally(() => pendingControllers.delete(controller));\n")
+	buf.WriteString("};\n\n")
+
+	buf.WriteString("export const abortAll = ()")
+	if typed {
+		buf.WriteString(": void")
+	}
+	buf.WriteString(" => {\n")
+	buf.WriteString(indent + "pendingControllers.forEach((controller) => controller.abort());\n")
+	buf.WriteString(indent + "pendingControllers.clear();\n")
+	buf.WriteString("};\n\n")
+}
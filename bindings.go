@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// extractAdditionalBindings 提取方法 google.api.http 注解中 additional_bindings 的 HTTP 规则，按声明顺序，无法识别的绑定跳过
+func extractAdditionalBindings(method *protogen.Method, defaultVerb string) []*HttpRule {
+	rule := httpRuleOf(method)
+	if rule == nil {
+		return nil
+	}
+	var rules []*HttpRule
+	for _, binding := range rule.GetAdditionalBindings() {
+		if r := httpRuleFromPattern(binding, defaultVerb); r != nil {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// bindingMethodName 返回 additional_bindings 生成的方法名：
+// 路径中有变量时以最后一个变量命名（如 GetGoods + /v1/goods/name/{name} -> GetGoodsByName），
+// 没有变量或与已有方法重名时按绑定序号（从 2 开始，主绑定为 1）追加数字（如 GetGoods2）
+// taken 为服务中已占用的方法名，返回的名称会加入其中
+func bindingMethodName(base string, rule *HttpRule, index int, taken map[string]bool) string {
+	name := ""
+	if _, vars := parsePathTemplate(rule.Path); len(vars) > 0 {
+		name = base + "By" + toPascalCase(snakeToCamel(lastFieldName(vars[len(vars)-1].FieldPath)))
+	}
+	if name == "" || taken[name] {
+		name = base + strconv.Itoa(index+1)
+		for n := index + 2; taken[name]; n++ {
+			name = base + strconv.Itoa(n)
+		}
+	}
+	taken[name] = true
+	return name
+}
+
+// lastFieldName 返回字段路径的最后一段（例如：book.book_id -> book_id）
+func lastFieldName(fieldPath string) string {
+	for i := len(fieldPath) - 1; i >= 0; i-- {
+		if fieldPath[i] == '.' {
+			return fieldPath[i+1:]
+		}
+	}
+	return fieldPath
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// bodyField 返回 HTTP 规则 body 指定的请求消息顶层字段（如 body: "book"），body 为 *、为空或字段不存在时返回 nil
+func bodyField(input *protogen.Message, body string) *protogen.Field {
+	if body == "" || body == "*" || input == nil {
+		return nil
+	}
+	for _, field := range input.Fields {
+		if string(field.Desc.Name()) == body {
+			return field
+		}
+	}
+	return nil
+}
+
+// bodyQueryFields 返回 body 指定字段时其余映射为查询参数的顶层字段（去掉路径参数与 body 字段）
+func bodyQueryFields(queryFields []string, bodyKey string) []string {
+	var fields []string
+	for _, field := range queryFields {
+		if field != bodyKey {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// bodyQuery 返回 body 指定字段时作为查询参数发送的其余字段（如 { updateMask: data.updateMask }），没有其余字段时为空
+// 开启 merge_defaults 时从合并默认值后的请求对象中取值，使默认值同样作用于查询参数
+func bodyQuery(data ServiceInfo, method MethodInfo) string {
+	if method.BodyField == "" || len(method.BodyQueryFields) == 0 {
+		return ""
+	}
+	source := data.ParamName
+	if mergesBody(data, method) {
+		source = mergedName(data)
+	}
+	entries := make([]string, len(method.BodyQueryFields))
+	for i, field := range method.BodyQueryFields {
+		entries[i] = field + ": " + source + "." + field
+	}
+	return "{ " + strings.Join(entries, ", ") + " }"
+}
+
+// bodyParams 返回 body 指定字段时作为请求选项 params 传给 service 的其余字段（如 params: { updateMask: data.updateMask }）；
+// client=fetch 时为空（request 的第四个参数为 RequestInit，其余字段改由 fetchCall 作为 queryParams 传入）
+func bodyParams(data ServiceInfo, method MethodInfo) string {
+	query := bodyQuery(data, method)
+	if query == "" || data.Client == "fetch" {
+		return ""
+	}
+	return "params: " + query
+}
+
+// mergesBody 判断方法是否需要先合并默认值再拆分请求对象：开启 merge_defaults 且 body 指定了字段时，
+// body 字段与其余查询参数都从方法体开头合并一次的请求对象（如 const merged = { ...xxxDefaults, ...data };）中取值
+func mergesBody(data ServiceInfo, method MethodInfo) bool {
+	return data.MergeDefaults && method.BodyField != "" && !method.NoRequest
+}
+
+// mergedName 返回合并默认值后的请求对象变量名，与 param_name 同名时改用 mergedData
+func mergedName(data ServiceInfo) string {
+	if data.ParamName == "merged" {
+		return "mergedData"
+	}
+	return "merged"
+}
+
+// mergedPreamble 返回方法体开头合并默认值的语句，indent 为语句缩进
+func mergedPreamble(data ServiceInfo, method MethodInfo, indent string) string {
+	return indent + "const " + mergedName(data) + " = { ..." + defaultsName(method) + ", ..." + data.ParamName + " };\n"
+}
+
+// hasBodyQuery 判断服务中是否有 body 指定字段且有其余字段需要作为查询参数的方法
+func hasBodyQuery(data ServiceInfo) bool {
+	for _, method := range data.Methods {
+		if method.BodyField != "" && len(method.BodyQueryFields) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestBodyFieldWithMergeDefaults(t *testing.T) {
+	file := orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")
+	// body 字段与其余查询参数都从合并一次的请求对象中取值
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js,merge_defaults=true", file)
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"  CreateOrder: (data: CreateOrderReq): Promise<Order> => {\n"+
+			"    const merged = { ...createOrderDefaults, ...data };\n"+
+			"    return service.post(`/v1/shop/shops/${encodeURIComponent(data.shopId)}/orders`, merged.order, { params: { remark: merged.remark } });\n"+
+			"  },",
+		// 没有 body 字段的方法仍直接发送合并后的对象
+		"service.get('/v1/shop/orders', { ...listOrdersDefaults, ...data })",
+	)
+	assertContains(t, generatedFile(t, generated, "js/orderApi.js"),
+		"    CreateOrder: (data) => {\n"+
+			"        const merged = { ...createOrderDefaults, ...data };\n",
+	)
+	assertNotContains(t, generatedFile(t, generated, "ts/orderApi.ts"), "remark: data.remark", "...data }).order")
+
+	generated = mustRunPlugin(t, "output_paths=ts,merge_defaults=true,client=fetch", file)
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"request<Order>('POST', `/v1/shop/shops/${encodeURIComponent(data.shopId)}/orders`, merged.order, undefined, { remark: merged.remark })",
+	)
+
+	// param_name=merged 时改用 mergedData，不与参数同名
+	generated = mustRunPlugin(t, "output_paths=ts,merge_defaults=true,param_name=merged", file)
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"const mergedData = { ...createOrderDefaults, ...merged };",
+		"mergedData.order, { params: { remark: mergedData.remark } }",
+	)
+}
+
+func TestBodyFieldWithBodyKeyCase(t *testing.T) {
+	file := orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js,body_key_case=snake", file)
+	// body 指定的字段为可选消息，renameKeys 遇到 undefined 时原样返回
+	ts := generatedFile(t, generated, "ts/orderApi.ts")
+	assertContains(t, ts,
+		"const renameKeys = (data: object | undefined, keys: Record<string, string>): Record<string, unknown> | undefined =>\n"+
+			"  data && Object.fromEntries(",
+		"renameKeys(data.order, createOrderBodyKeys), { params: { remark: data.remark } }",
+	)
+	assertContains(t, generatedFile(t, generated, "js/orderApi.js"), "const renameKeys = (data, keys) =>\n    data && Object.fromEntries(")
+
+	generated = mustRunPlugin(t, "output_paths=ts,body_key_case=snake,merge_defaults=true", file)
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"renameKeys(merged.order, createOrderBodyKeys), { params: { remark: merged.remark } }",
+	)
+}
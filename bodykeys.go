@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// bodyKeyCases body_key_case 支持的取值
+var bodyKeyCases = map[string]bool{"snake": true, "camel": true, "pascal": true, "kebab": true}
+
+// hasBody 判断方法是否发送请求体（post/put/patch），body_key_case 只改写这些方法的数据
+func hasBody(method MethodInfo) bool {
+	switch method.HttpMethod {
+	case "post", "put", "patch":
+		return true
+	}
+	return false
+}
+
+// bodyKeyName 将 proto 字段名（snake_case）按 body_key_case 转换为请求体中的键名
+func bodyKeyName(protoName, keyCase string) string {
+	switch keyCase {
+	case "snake":
+		return protoName
+	case "kebab":
+		return strings.ReplaceAll(protoName, "_", "-")
+	case "pascal":
+		return toPascalCase(snakeToCamel(protoName))
+	}
+	return snakeToCamel(protoName)
+}
+
+// buildBodyKeys 返回请求消息顶层字段键名到请求体键名的映射，只包含需要改名的字段
+func buildBodyKeys(msg *protogen.Message, keyCase string, useJSON bool) []exampleEntry {
+	var keys []exampleEntry
+	// Struct 等任意 JSON 请求体的键由调用方决定，不改写
+	if isJSONWellKnown(msg) {
+		return nil
+	}
+	for _, field := range msg.Fields {
+		from := fieldKey(field, useJSON)
+		if to := bodyKeyName(string(field.Desc.Name()), keyCase); to != from {
+			keys = append(keys, exampleEntry{Key: from, Value: to})
+		}
+	}
+	return keys
+}
+
+// bodyKeysName 返回方法请求体键名映射常量名（例如：CreateOrder -> createOrderBodyKeys）
+func bodyKeysName(method MethodInfo) string {
+	return toCamelCase(method.MethodName) + "BodyKeys"
+}
+
+// renameBodyKeys 需要改写请求体键名时，将数据表达式包裹为 renameKeys(expr, xxxBodyKeys)
+func renameBodyKeys(data ServiceInfo, method MethodInfo, expr string) string {
+	if data.BodyKeyCase == "" || len(method.BodyKeys) == 0 {
+		return expr
+	}
+	return "renameKeys(" + expr + ", " + bodyKeysName(method) + ")"
+}
+
+// writeBodyKeys 生成 renameKeys 辅助函数及每个方法的键名映射常量（只改写顶层键）
+// typed 为 true 时生成 TS 类型标注，indent 为每层缩进
+func writeBodyKeys(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if data.BodyKeyCase == "" {
+		return
+	}
+	var methods []MethodInfo
+	for _, method := range data.Methods {
+		if len(method.BodyKeys) > 0 {
+			methods = append(methods, method)
+		}
+	}
+	if len(methods) == 0 {
+		return
+	}
+	// body 指定的字段为可选消息，可能为 undefined，此时原样返回
+	if typed {
+		buf.WriteString("const renameKeys = (data: object | undefined, keys: Record<string, string>): Record<string, unknown> | undefined =>\n")
+	} else {
+		buf.WriteString("const renameKeys = (data, keys) =>\n")
+	}
+	buf.WriteString(indent)
+	buf.WriteString("data && Object.fromEntries(Object.entries(data).map(([key, value]) => [keys[key] ?? key, value]));\n\n")
+	for _, method := range methods {
+		buf.WriteString("const ")
+		buf.WriteString(bodyKeysName(method))
+		buf.WriteString(" = ")
+		buf.WriteString(renderExample(exampleObject(method.BodyKeys), "", indent, data.TrailingComma))
+		buf.WriteString(";\n\n")
+	}
+}
+
+// validateBodyKeyCase 校验 body_key_case 的取值
+func validateBodyKeyCase(value string) error {
+	if !bodyKeyCases[value] {
+		return fmt.Errorf("body_key_case 只支持 snake、camel、pascal、kebab: %s", value)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// itemFile 返回 body 为 * 的 CreateItem 与 GET 方法 GetItem，请求字段含多个单词
+func itemFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("shop/v1/item.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("CreateItemReq",
+				protoField("item_name", 1, typeString, ""),
+				protoField("unit_price", 2, typeInt32, ""),
+				protoField("sku", 3, typeString, ""),
+			),
+			protoMessage("Item", protoField("item_name", 1, typeString, "")),
+		},
+		protoService("ItemService",
+			protoMethod("CreateItem", ".shop.v1.CreateItemReq", ".shop.v1.Item", httpPost("/v1/items", "*")),
+			protoMethod("GetItem", ".shop.v1.CreateItemReq", ".shop.v1.Item", httpGet("/v1/items/{sku}")),
+		),
+	)
+}
+
+func TestBodyKeyCase(t *testing.T) {
+	tests := []struct {
+		keyCase string
+		keys    string // createItemBodyKeys 的内容，为空表示键名不变、不生成映射
+	}{
+		{"snake", "  itemName: 'item_name',\n  unitPrice: 'unit_price',\n"},
+		{"pascal", "  itemName: 'ItemName',\n  unitPrice: 'UnitPrice',\n  sku: 'Sku',\n"},
+		{"kebab", "  itemName: 'item-name',\n  unitPrice: 'unit-price',\n"},
+		{"camel", ""},
+	}
+	for _, tt := range tests {
+		generated := mustRunPlugin(t, "output_paths=ts,body_key_case="+tt.keyCase, itemFile())
+		code := generatedFile(t, generated, "ts/itemApi.ts")
+		if tt.keys == "" {
+			assertContains(t, code, "service.post('/v1/items', data)")
+			assertNotContains(t, code, "renameKeys", "BodyKeys")
+			continue
+		}
+		assertContains(t, code,
+			"const createItemBodyKeys = {\n"+tt.keys+"};",
+			"service.post('/v1/items', renameKeys(data, createItemBodyKeys))",
+		)
+		// GET 请求没有请求体，不改写键名
+		assertContains(t, code, "service.get(`/v1/items/${encodeURIComponent(data.sku)}`, data)")
+		assertNotContains(t, code, "getItemBodyKeys")
+	}
+}
+
+func TestBodyKeyCaseInvalid(t *testing.T) {
+	_, err := runPlugin("output_paths=ts,body_key_case=upper", itemFile())
+	assertErrorContains(t, err, "body_key_case 只支持 snake、camel、pascal、kebab: upper")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBundleDts(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths_js=js,bundle_dts=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"))
+	code := generatedFile(t, generated, "js/api.d.ts")
+	if !strings.HasPrefix(code, "// Code generated by protoc-gen-frontend-api. DO NOT EDIT.\n") {
+		t.Errorf("api.d.ts 缺少生成文件头部:\n%s", code)
+	}
+	assertContains(t, code,
+		"import type { CreateOrderReq, GetOrderReq, ListOrdersReq, ListOrdersResp, Order } from '@/api/proto-types/shop/v1/order';",
+		"export interface OrderApi {\n  GetOrder(data: GetOrderReq): Promise<Order>;",
+		"export interface Api {\n  orderApi: OrderApi;\n}",
+	)
+	assertNotContains(t, code, "// source:")
+}
+
+func TestBundleDtsWithoutBanner(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths_js=js,bundle_dts=true,banner=false",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"))
+	code := generatedFile(t, generated, "js/api.d.ts")
+	if !strings.HasPrefix(code, "import type {") {
+		t.Errorf("banner=false 时 api.d.ts 不应有头部注释:\n%s", code)
+	}
+}
+
+func TestBundleDtsRejectsDuplicateTypeNames(t *testing.T) {
+	_, err := runPlugin("output_paths_js=js,bundle_dts=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		orderFile("admin/v1/order.proto", "admin.v1", "AdminOrderService", "admin"))
+	assertErrorContains(t, err, "api.d.ts 中的类型 Order 同时来自 @/api/proto-types/shop/v1/order 与 @/api/proto-types/admin/v1/order")
+}
+
+func TestBundleDtsRejectsDuplicateServiceNames(t *testing.T) {
+	// package_dirs 时两个包下的 OrderService 分别写入 shop/v1/orderApi.js 与 admin/v1/orderApi.js，
+	// 但在 api.d.ts 中都会声明 OrderApi
+	_, err := runPlugin("output_paths_js=js,bundle_dts=true,package_dirs=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		orderFile("admin/v1/order.proto", "admin.v1", "OrderService", "admin"))
+	assertErrorContains(t, err, "api.d.ts 中的类型 OrderApi 同时来自 服务 shop.v1.OrderService 与 服务 admin.v1.OrderService")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// wrapCache 开启 cache_get 时将 GET 方法的调用包裹为 cached(key, () => call)，
+// key 为服务名.方法名加序列化后的 data（含路径参数与查询参数，没有请求参数的方法只有方法名），flatten 时各服务共用一个缓存也不会冲突
+func wrapCache(data ServiceInfo, method MethodInfo, call string) string {
+	if data.CacheGet <= 0 || method.HttpMethod != "get" {
+		return call
+	}
+	if method.NoRequest {
+		return "cached('" + data.ServiceName + "." + method.MethodName + "', () => " + call + ")"
+	}
+	return "cached(`" + data.ServiceName + "." + method.MethodName + ":${JSON.stringify(" + data.ParamName + ")}`, () => " + call + ")"
+}
+
+// writeCacheHelper 开启 cache_get 时生成模块内的 GET 缓存：相同 key 在 TTL 内复用同一个 Promise（并发请求也只发一次），
+// 请求失败时立即移除缓存；导出 clearCache() 用于在写操作后主动清空
+// typed 为 true 时生成 TS 类型标注，indent 为每层缩进
+func writeCacheHelper(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if data.CacheGet <= 0 {
+		return
+	}
+	in1, in2 := indent, indent+indent
+	buf.WriteString("const CACHE_TTL = ")
+	buf.WriteString(strconv.Itoa(data.CacheGet * 1000))
+	buf.WriteString(";\n\n")
+	if typed {
+		buf.WriteString("const getCache = new Map<string, { expires: number; value: Promise<unknown> }>();\n\n")
+		buf.WriteString("const cached = <T>(key: string, call: () => Promise<T>): Promise<T> => {\n")
+	} else {
+		buf.WriteString("const getCache = new Map();\n\n")
+		buf.WriteString("const cached = (key, call) => {\n")
+	}
+	buf.WriteString(in1 + "const now = Date.now();\n")
+	buf.WriteString(in1 + "const hit = getCache.get(key);\n")
+	buf.WriteString(in1 + "if (hit && hit.expires > now) {\n")
+	if typed {
+		buf.WriteString(in2 + "return hit.value as Promise<T>;\n")
+	} else {
+		buf.WriteString(in2 + "return hit.value;\n")
+	}
+	buf.WriteString(in1 + "}\n")
+	buf.WriteString(in1 + "const value = call();\n")
+	buf.WriteString(in1 + "getCache.set(key, { expires: now + CACHE_TTL, value });\n")
+	buf.WriteString(in1 + "value.catch(() => getCache.delete(key));\n")
+	buf.WriteString(in1 + "return value;\n")
+	buf.WriteString("};\n\n")
+
+	buf.WriteString("export const clearCache = ()")
+	if typed {
+		buf.WriteString(": void")
+	}
+	buf.WriteString(" => {\n")
+	buf.WriteString(in1 + "getCache.clear();\n")
+	buf.WriteString("};\n\n")
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// twoServiceFile 返回同一文件中的两个服务，各有一个 POST 方法
+func twoServiceFile(first, second string) *descriptorpb.FileDescriptorProto {
+	return protoFile("api/v1/api.proto", "api.v1",
+		[]*descriptorpb.DescriptorProto{protoMessage("PingReq", protoField("id", 1, typeString, ""))},
+		protoService(first, protoMethod("Ping", ".api.v1.PingReq", ".google.protobuf.Empty", httpPost("/v1/"+first+"/ping", "*"))),
+		protoService(second, protoMethod("Ping", ".api.v1.PingReq", ".google.protobuf.Empty", httpPost("/v1/"+second+"/ping", "*"))),
+	)
+}
+
+func TestServicesWithSameCamelCaseName(t *testing.T) {
+	// 默认开头缩写词整体小写：APIService 与 ApiService 都生成 apiApi
+	_, err := runPlugin("output_paths=ts", twoServiceFile("APIService", "ApiService"))
+	assertErrorContains(t, err, "服务 api.v1.APIService（api/v1/api.proto）与服务 api.v1.ApiService（api/v1/api.proto）生成的文件名相同: apiApi")
+}
+
+func TestServicesWithCaseOnlyDifferentNames(t *testing.T) {
+	// first_acronym=first 时生成 aPIApi 与 apiApi，在大小写不敏感的文件系统上会互相覆盖
+	_, err := runPlugin("output_paths=ts,first_acronym=first", twoServiceFile("APIService", "ApiService"))
+	assertErrorContains(t, err, "服务 api.v1.ApiService 生成的文件名 apiApi 与服务 api.v1.APIService 的 aPIApi 仅大小写不同")
+}
+
+func TestServicesWithSameNameAfterSuffix(t *testing.T) {
+	_, err := runPlugin("output_paths=ts", twoServiceFile("Goods", "GoodsService"))
+	assertErrorContains(t, err, "生成的文件名相同: goodsApi")
+
+	// 调整 strip_suffix 后不再冲突
+	generated := mustRunPlugin(t, "output_paths=ts,strip_suffix=Svc", twoServiceFile("Goods", "GoodsService"))
+	generatedFile(t, generated, "ts/goodsApi.ts")
+	generatedFile(t, generated, "ts/goodsServiceApi.ts")
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// getServiceComment 返回服务的前置注释（已去除首尾空白）
+// deep 为 true 时，protogen 的 service.Comments 为空则直接遍历文件 SourceCodeInfo 中与服务路径匹配的位置，
+// 依次取前置注释、最后一段分离注释（与服务定义之间隔有空行的注释）
+func getServiceComment(file *protogen.File, service *protogen.Service, deep bool) string {
+	if comment := strings.TrimSpace(string(service.Comments.Leading)); comment != "" || !deep {
+		return comment
+	}
+	// FileDescriptorProto 中 service 字段编号为 6，路径为 [6, 服务下标]
+	want := []int32{6, int32(service.Desc.Index())}
+	for _, loc := range file.Proto.GetSourceCodeInfo().GetLocation() {
+		path := loc.GetPath()
+		if len(path) != len(want) || path[0] != want[0] || path[1] != want[1] {
+			continue
+		}
+		if comment := strings.TrimSpace(loc.GetLeadingComments()); comment != "" {
+			return comment
+		}
+		if detached := loc.GetLeadingDetachedComments(); len(detached) > 0 {
+			return strings.TrimSpace(detached[len(detached)-1])
+		}
+	}
+	return ""
+}
+
+// serviceImportDirective 服务注释中指定该服务 service 导入的指令，如 // @frontend:service_import ./adminRequest
+const serviceImportDirective = "@frontend:service_import"
+
+// parseServiceImportDirective 从服务注释中取出 @frontend:service_import 指令的导入路径，并从注释中去掉指令所在行
+// 没有指令时返回空串及原注释；多次出现时以最后一次为准
+func parseServiceImportDirective(comment string) (string, string) {
+	importPath := ""
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), serviceImportDirective); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			importPath = strings.TrimSpace(rest)
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return importPath, strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// writeLineComment 将多行注释逐行写为 // 注释，空行写为单独的 //
+func writeLineComment(buf *bytes.Buffer, comment string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			buf.WriteString("//\n")
+			continue
+		}
+		buf.WriteString("// ")
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+}
+
+// jsDocComment 将方法注释及标签（如 @group Orders）渲染为 indent 缩进的 JSDoc 块（每行以 * 开头，含结尾换行），
+// 注释与标签都为空时返回空串
+func jsDocComment(comment string, tags []string, indent string) string {
+	if comment == "" && len(tags) == 0 {
+		return ""
+	}
+	// 没有注释、只有一个标签时写成单行，如 /** @deprecated */
+	if comment == "" && len(tags) == 1 {
+		return indent + "/** " + tags[0] + " */\n"
+	}
+	var lines []string
+	if comment != "" {
+		lines = strings.Split(comment, "\n")
+	}
+	lines = append(lines, tags...)
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		// 避免注释中的 */ 提前结束 JSDoc 块
+		line = strings.ReplaceAll(strings.TrimSpace(line), "*/", "*\\/")
+		if line == "" {
+			b.WriteString(indent + " *\n")
+			continue
+		}
+		b.WriteString(indent + " * " + line + "\n")
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
+
+// methodTags 返回写入方法 JSDoc 的标签
+func methodTags(data ServiceInfo, method MethodInfo) []string {
+	var tags []string
+	if method.Deprecated {
+		tags = append(tags, "@deprecated")
+	}
+	if data.EmitGroupTags {
+		if group := pathGroup(method.HttpPath); group != "" {
+			tags = append(tags, "@group "+group)
+		}
+	}
+	return tags
+}
+
+// pathGroup 从 HTTP 路径推断方法所属的资源：取最后一个非版本号（如 v1）的字面段，去掉 :verb 后缀并转为 PascalCase
+// 例如：/v1/shops/{shop_id}/orders -> Orders，/v1/orders/{order_id} -> Orders，/v1/goods:export -> Goods
+func pathGroup(path string) string {
+	literals, _ := parsePathTemplate(path)
+	group := ""
+	for _, literal := range literals {
+		for _, segment := range strings.Split(literal, "/") {
+			if i := strings.Index(segment, ":"); i >= 0 {
+				segment = segment[:i]
+			}
+			if segment == "" || isVersionSegment(segment) {
+				continue
+			}
+			group = segment
+		}
+	}
+	if group == "" {
+		return ""
+	}
+	return toPascalCase(snakeToCamel(strings.ReplaceAll(group, "-", "_")))
+}
+
+// isVersionSegment 判断路径段是否为版本号（如 v1、v2beta1）
+func isVersionSegment(segment string) bool {
+	if len(segment) < 2 || segment[0] != 'v' || segment[1] < '0' || segment[1] > '9' {
+		return false
+	}
+	return true
+}
+
+// writeServiceDeprecation 服务标记了 option deprecated = true 时，在文件开头写入提示注释
+func writeServiceDeprecation(buf *bytes.Buffer, data ServiceInfo) {
+	if !data.Deprecated {
+		return
+	}
+	buf.WriteString("// 已废弃：proto 服务 ")
+	buf.WriteString(data.FullName)
+	buf.WriteString(" 标记了 deprecated，请勿在新代码中使用\n\n")
+}
+
+// writeObjectDeprecation 服务已废弃时在 API 对象上方写入 /** @deprecated */，使编辑器对其引用给出提示
+func writeObjectDeprecation(buf *bytes.Buffer, data ServiceInfo) {
+	if data.Deprecated {
+		buf.WriteString("/** @deprecated */\n")
+	}
+}
+
+// lintIgnoreDirectives lint_ignore 支持的取值及对应的文件头部指令
+var lintIgnoreDirectives = map[string]string{
+	"eslint":   "/* eslint-disable */",
+	"prettier": "// prettier-ignore",
+}
+
+// parseLintIgnore 解析 lint_ignore：; 分隔的 eslint、prettier，true 表示两者，false 表示不写入
+func parseLintIgnore(value string) ([]string, error) {
+	switch value {
+	case "true":
+		return []string{"eslint", "prettier"}, nil
+	case "false":
+		return nil, nil
+	}
+	items := parseSuffixList(value)
+	for _, item := range items {
+		if _, ok := lintIgnoreDirectives[item]; !ok {
+			return nil, fmt.Errorf("lint_ignore 只支持 eslint、prettier、true: %s", item)
+		}
+	}
+	return items, nil
+}
+
+// writeLintIgnore 按 lint_ignore 的顺序写入忽略指令
+func writeLintIgnore(buf *bytes.Buffer, ignores []string) {
+	for _, item := range ignores {
+		buf.WriteString(lintIgnoreDirectives[item])
+		buf.WriteString("\n")
+	}
+}
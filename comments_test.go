@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// commentedOrderFile 返回订单服务：服务注释只以分离注释出现在 SourceCodeInfo 中（protogen 的 service.Comments 为空），
+// 另有方法及嵌套消息 Order.Line 字段的注释
+func commentedOrderFile() *descriptorpb.FileDescriptorProto {
+	file := orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")
+	order := file.MessageType[0]
+	order.NestedType = append(order.NestedType, protoMessage("Line", protoField("sku", 1, typeString, "")))
+	order.Field = append(order.Field, protoField("line", 3, typeMessage, ".shop.v1.Order.Line"))
+	// 路径：4 消息、3 嵌套消息、2 字段、6 服务
+	withComments(file, []int32{4, 0, 3, 0, 2, 0}, " 商品编码\n", "")
+	withComments(file, []int32{4, 0, 2, 2}, " 订单行\n", "")
+	withComments(file, []int32{6, 0, 2, 0}, " 查询订单\n", "")
+	file.SourceCodeInfo.Location = append(file.SourceCodeInfo.Location, &descriptorpb.SourceCodeInfo_Location{
+		Path:                    []int32{6, 0},
+		Span:                    []int32{0, 0, 1},
+		LeadingDetachedComments: []string{" 订单服务\n"},
+	})
+	return file
+}
+
+func TestDeepComments(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,deep_comments=true,emit_interfaces=true", commentedOrderFile())
+	// 服务注释取自服务路径 [6, 0] 的分离注释，不会取到方法 [6, 0, 2, 0] 的注释
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"), "// 订单服务\nexport const orderApi = {\n")
+	assertNotContains(t, generatedFile(t, generated, "ts/orderApi.ts"), "// 查询订单")
+	// 嵌套消息的字段注释照常生成
+	assertContains(t, generatedFile(t, generated, "ts/types.ts"),
+		"  /**\n   * 订单行\n   */\n  line?: Order_Line;\n",
+		"export interface Order_Line {\n  /**\n   * 商品编码\n   */\n  sku: string;\n}",
+	)
+
+	// 未开启时 protogen 的服务注释为空，不生成服务注释
+	generated = mustRunPlugin(t, "output_paths=ts,emit_interfaces=true", commentedOrderFile())
+	assertNotContains(t, generatedFile(t, generated, "ts/orderApi.ts"), "订单服务")
+	assertContains(t, generatedFile(t, generated, "ts/types.ts"), "   * 商品编码\n")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compatFields 返回 compat_args 时位置参数对应的请求字段键名，按 proto 字段声明顺序
+func compatFields(data ServiceInfo, method MethodInfo) []string {
+	keys := make([]string, len(method.Input.Fields))
+	for i, field := range method.Input.Fields {
+		keys[i] = fieldKey(field, data.UseJSONNames)
+	}
+	return keys
+}
+
+// compatParams 返回 compat_args 时的方法参数：...args，typed 为 true 时标注为「单个对象」与「按字段顺序的位置参数」两种元组的联合
+// 例如：...args: [data: CreateOrderReq] | [shopId: CreateOrderReq['shopId'], order: CreateOrderReq['order']]
+func compatParams(data ServiceInfo, method MethodInfo, typed bool) string {
+	if !typed {
+		return "...args"
+	}
+	positional := make([]string, 0, len(method.Input.Fields))
+	for _, key := range compatFields(data, method) {
+		positional = append(positional, key+": "+method.RequestType+"['"+key+"']")
+	}
+	return "...args: [" + data.ParamName + ": " + requestParamType(data, method) + "] | [" + strings.Join(positional, ", ") + "]"
+}
+
+// compatPreamble 返回 compat_args 时方法体开头的参数归一化语句：只传一个非 null 对象时视为完整的请求对象，
+// 否则按字段顺序将位置参数组装为请求对象（请求消息第一个字段为消息类型时，只传该字段会被当作完整请求对象）
+func compatPreamble(data ServiceInfo, method MethodInfo, indent string, typed bool) string {
+	fields := compatFields(data, method)
+	entries := make([]string, len(fields))
+	for i, key := range fields {
+		entries[i] = exampleKey(key) + ": args[" + strconv.Itoa(i) + "]"
+	}
+	object := "{}"
+	if len(entries) > 0 {
+		object = "{ " + strings.Join(entries, ", ") + " }"
+	}
+	expr := "args.length === 1 && typeof args[0] === 'object' && args[0] !== null ? args[0] : " + object
+	if typed {
+		expr = "(" + expr + ") as " + requestParamType(data, method)
+	}
+	return indent + "const " + data.ParamName + " = " + expr + ";\n"
+}
+
+// withCompatArgs 在块体方法体（=> {\n 开头）中插入方法开头的语句（compat_args 的参数归一化、merge_defaults 的默认值合并）
+func withCompatArgs(body, preamble string) string {
+	return strings.Replace(body, "=> {\n", "=> {\n"+preamble, 1)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// configVarName 返回模块级配置变量名（例如：orderApi -> orderApiConfig）
+func configVarName(data ServiceInfo) string {
+	return data.ApiFileName + "Config"
+}
+
+// configTypeName 返回模块配置类型名（例如：orderApi -> OrderApiConfig）
+func configTypeName(data ServiceInfo) string {
+	return toPascalCase(data.ApiFileName) + "Config"
+}
+
+// withBaseURL 开启 emit_configure 时在路径表达式前拼接模块配置的 baseURL
+func withBaseURL(data ServiceInfo, path string) string {
+	if !data.EmitConfigure {
+		return path
+	}
+	prefix := "`${" + configVarName(data) + ".baseURL ?? ''}"
+	switch {
+	case strings.HasPrefix(path, "`"):
+		return prefix + path[1:]
+	case strings.HasPrefix(path, "'"):
+		return prefix + strings.TrimSuffix(path[1:], "'") + "`"
+	}
+	return path
+}
+
+// requestOptions 返回传给 service 的请求选项属性：emit_configure 时带 headers，emit_abort_all 时带 signal
+func requestOptions(data ServiceInfo) []string {
+	var options []string
+	if data.EmitConfigure {
+		options = append(options, "headers: "+configVarName(data)+".headers")
+	}
+	if data.EmitAbortAll {
+		options = append(options, "signal")
+	}
+	return options
+}
+
+// configOptions 返回作为第三个参数传给 service 的请求选项，没有选项时为空
+func configOptions(data ServiceInfo) string {
+	options := requestOptions(data)
+	if len(options) == 0 {
+		return ""
+	}
+	return ", { " + strings.Join(options, ", ") + " }"
+}
+
+// writeConfigure 生成模块级配置及导出的 configure(cfg) 函数：配置按字段合并保存，之后该模块的所有调用
+// 都以 baseURL 为路径前缀，并将 headers 作为请求选项传给 service
+// typed 为 true 时生成 TS 类型，indent 为每层缩进
+func writeConfigure(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if !data.EmitConfigure {
+		return
+	}
+	if typed {
+		buf.WriteString("export interface ")
+		buf.WriteString(configTypeName(data))
+		buf.WriteString(" {\n")
+		buf.WriteString(indent + "baseURL?: string;\n")
+		buf.WriteString(indent + "headers?: Record<string, string>;\n")
+		buf.WriteString("}\n\n")
+	}
+
+	buf.WriteString("let ")
+	buf.WriteString(configVarName(data))
+	if typed {
+		buf.WriteString(": ")
+		buf.WriteString(configTypeName(data))
+	}
+	buf.WriteString(" = {};\n\n")
+
+	buf.WriteString("export const configure = (cfg")
+	if typed {
+		buf.WriteString(": ")
+		buf.WriteString(configTypeName(data))
+		buf.WriteString("): void")
+	} else {
+		buf.WriteString(")")
+	}
+	buf.WriteString(" => {\n")
+	buf.WriteString(indent)
+	buf.WriteString(configVarName(data))
+	buf.WriteString(" = { ...")
+	buf.WriteString(configVarName(data))
+	buf.WriteString(", ...cfg };\n")
+	buf.WriteString("};\n\n")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// validateProtocol 校验 protocol 的取值
+func validateProtocol(value string) error {
+	if value != "rest" && value != "connect" {
+		return fmt.Errorf("protocol 只支持 rest、connect: %s", value)
+	}
+	return nil
+}
+
+// connectRule protocol=connect 时方法使用的调用规则：忽略 HTTP 注解，按 connect 协议以 POST 调用 /包名.服务名/方法名
+// （路径只用于 emit_paths、emit_path_builders 等，实际请求由注入的客户端发出）
+func connectRule(service, method string) *HttpRule {
+	return &HttpRule{Method: "POST", Path: "/" + service + "/" + method}
+}
+
+// connectClientType 返回 protocol=connect 时注入的客户端类型名（例如：Order -> OrderClient）
+func connectClientType(data ServiceInfo) string {
+	return data.ServiceName + "Client"
+}
+
+// connectCall 返回 protocol=connect 时调用注入客户端的表达式（例如：client.getOrder(data)），
+// 方法名与 connect-es 生成的客户端一致（首字母小写）；请求选项（headers、signal）作为第二个参数传入，与 connect 的 CallOptions 一致
+func connectCall(data ServiceInfo, method MethodInfo) string {
+	arg := requestData(data, method)
+	if method.NoRequest {
+		arg = "{}"
+	}
+	return "client." + lowerFirst(method.MethodName) + "(" + arg + configOptions(data) + ")"
+}
+
+// writeConnectClient protocol=connect 时生成客户端类型（TS）、模块内的 client 变量及注入客户端的 setClient：
+// 客户端通常为 connect-es 的 createClient(XxxService, transport)，只需在结构上满足 XxxClient
+// typed 为 true 时生成 TS 类型，indent 为每层缩进
+func writeConnectClient(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if data.Protocol != "connect" {
+		return
+	}
+	client := connectClientType(data)
+	if typed {
+		buf.WriteString("export interface ")
+		buf.WriteString(client)
+		buf.WriteString(" {\n")
+		for _, method := range data.Methods {
+			// streaming=stub 的流式方法不经过客户端调用，不声明
+			if method.StreamKind != "" {
+				continue
+			}
+			buf.WriteString(indent)
+			buf.WriteString(lowerFirst(method.MethodName))
+			buf.WriteString("(request: ")
+			buf.WriteString(method.RequestType)
+			buf.WriteString(", options?: { headers?: Record<string, string>; signal?: AbortSignal }): Promise<")
+			buf.WriteString(method.ResponseType)
+			buf.WriteString(">;\n")
+		}
+		buf.WriteString("}\n\n")
+		buf.WriteString("let client: " + client + ";\n\n")
+		buf.WriteString("// setClient 注入 connect 客户端，如 setClient(createClient(" + data.ServiceName + "Service, transport))，须在调用 API 之前完成\n")
+		buf.WriteString("export const setClient = (c: " + client + "): void => {\n")
+	} else {
+		buf.WriteString("let client;\n\n")
+		buf.WriteString("// setClient 注入 connect 客户端，如 setClient(createClient(" + data.ServiceName + "Service, transport))，须在调用 API 之前完成\n")
+		buf.WriteString("export const setClient = (c) => {\n")
+	}
+	buf.WriteString(indent + "client = c;\n")
+	buf.WriteString("};\n\n")
+}
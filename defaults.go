@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// rawExpr 原样输出的 JS 表达式（如 new Uint8Array(0)）
+type rawExpr string
+
+// defaultsName 返回方法请求默认值常量名（例如：CreateOrder -> createOrderDefaults）
+func defaultsName(method MethodInfo) string {
+	return toCamelCase(method.MethodName) + "Defaults"
+}
+
+// buildDefaults 构造请求消息的默认值对象，与 ts-proto 的 createBaseXxx 一致：
+// 标量与枚举为零值（proto2 / editions 声明了 default 时使用声明值），repeated 为 []，map 为 {}，消息字段与 oneof 成员不设默认值
+func buildDefaults(msg *protogen.Message, useJSON bool) exampleObject {
+	obj := exampleObject{}
+	if isJSONWellKnown(msg) {
+		return obj
+	}
+	for _, field := range msg.Fields {
+		if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+			continue
+		}
+		var value interface{}
+		switch {
+		case field.Desc.IsMap():
+			value = exampleObject{}
+		case field.Desc.IsList():
+			value = []interface{}{}
+		case field.Message != nil || field.Desc.HasOptionalKeyword():
+			// 消息字段与 proto3 optional 字段在 ts-proto 中默认为 undefined
+			continue
+		default:
+			value = defaultValue(field)
+		}
+		obj = append(obj, exampleEntry{Key: fieldKey(field, useJSON), Value: value})
+	}
+	return obj
+}
+
+// defaultValue 返回标量/枚举字段的默认值（ts-proto 表示：枚举为数值，64 位整数为 number，bytes 为 Uint8Array）
+func defaultValue(field *protogen.Field) interface{} {
+	d := field.Desc.Default()
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return d.Bool()
+	case protoreflect.StringKind:
+		return d.String()
+	case protoreflect.BytesKind:
+		return rawExpr("new Uint8Array(0)")
+	case protoreflect.EnumKind:
+		return json.Number(strconv.Itoa(int(d.Enum())))
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		switch f := d.Float(); {
+		case math.IsInf(f, 1):
+			return rawExpr("Infinity")
+		case math.IsInf(f, -1):
+			return rawExpr("-Infinity")
+		case math.IsNaN(f):
+			return rawExpr("NaN")
+		default:
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return json.Number(strconv.FormatUint(d.Uint(), 10))
+	}
+	return json.Number(strconv.FormatInt(d.Int(), 10))
+}
+
+// writeDefaults 为每个方法生成请求默认值常量（不导出），调用时与调用方数据合并：{ ...xxxDefaults, ...data }
+// 没有请求参数的方法不发送数据，不生成
+func writeDefaults(buf *bytes.Buffer, data ServiceInfo, indent string) {
+	if !data.MergeDefaults {
+		return
+	}
+	for _, method := range data.Methods {
+		if method.NoRequest {
+			continue
+		}
+		buf.WriteString("const ")
+		buf.WriteString(defaultsName(method))
+		buf.WriteString(" = ")
+		buf.WriteString(renderExample(method.Defaults, "", indent, data.TrailingComma))
+		buf.WriteString(";\n\n")
+	}
+}
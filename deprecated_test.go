@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// deprecatedField 将字段标记为 [deprecated = true]
+func deprecatedField(field *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	field.Options = &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)}
+	return field
+}
+
+// deprecatedFieldFile 返回响应消息 Order 中含废弃字段（标量 legacy_code 与消息字段 legacy_owner）的服务
+func deprecatedFieldFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("shop/v1/order.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("Order",
+				protoField("order_id", 1, typeString, ""),
+				deprecatedField(protoField("legacy_code", 2, typeString, "")),
+				deprecatedField(protoField("legacy_owner", 3, typeMessage, ".shop.v1.Owner")),
+			),
+			protoMessage("Owner", protoField("name", 1, typeString, "")),
+			protoMessage("GetOrderReq", protoField("order_id", 1, typeString, "")),
+		},
+		protoService("OrderService",
+			protoMethod("GetOrder", ".shop.v1.GetOrderReq", ".shop.v1.Order", httpGet("/v1/orders/{order_id}")),
+		),
+	)
+}
+
+func TestDeprecatedFieldInInterfaces(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,emit_interfaces=true", deprecatedFieldFile())
+	code := generatedFile(t, generated, "ts/types.ts")
+	assertContains(t, code,
+		"  orderId: string;\n  /** @deprecated */\n  legacyCode: string;\n  /** @deprecated */\n  legacyOwner?: Owner;",
+	)
+	assertNotContains(t, code, "/** @deprecated */\n  orderId", "/** @deprecated */\n  name")
+}
+
+func TestDeprecatedFieldInStrictTypes(t *testing.T) {
+	// 重新声明的字段不会继承 ts-proto 的 JSDoc，StrictXxx 中需要自行标注
+	generated := mustRunPlugin(t, "output_paths=ts,strict_null=true", deprecatedFieldFile())
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"export type StrictOrder = Omit<Order, 'legacyOwner'> & {\n  /** @deprecated */\n  legacyOwner?: Order['legacyOwner'];\n};",
+	)
+}
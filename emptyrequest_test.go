@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// healthFile 返回请求为 google.protobuf.Empty 或没有字段的消息的服务，以及一个普通方法作对照
+func healthFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("ops/v1/health.proto", "ops.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("NoArgs"),
+			protoMessage("Status", protoField("ok", 1, typeBool, "")),
+			protoMessage("CheckReq", protoField("component", 1, typeString, "")),
+		},
+		protoService("HealthService",
+			protoMethod("GetHealth", ".google.protobuf.Empty", ".ops.v1.Status", httpGet("/v1/health")),
+			protoMethod("Reset", ".ops.v1.NoArgs", ".google.protobuf.Empty", httpPost("/v1/reset", "*")),
+			protoMethod("Check", ".ops.v1.CheckReq", ".ops.v1.Status", httpGet("/v1/check")),
+		),
+	)
+}
+
+func TestEmptyRequestOmitsDataParameter(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js", healthFile())
+	ts := generatedFile(t, generated, "ts/healthApi.ts")
+	assertContains(t, ts,
+		"GetHealth: (): Promise<Status> =>\n    service.get('/v1/health')",
+		"Reset: (): Promise<Empty> =>\n    service.post('/v1/reset')",
+		"Check: (data: CheckReq): Promise<Status> =>\n    service.get('/v1/check', data)",
+	)
+	// 没有字段的请求消息不需要导入
+	assertNotContains(t, ts, "NoArgs")
+	js := generatedFile(t, generated, "js/healthApi.js")
+	assertContains(t, js,
+		"GetHealth: () => service.get('/v1/health')",
+		"Reset: () => service.post('/v1/reset')",
+		"Check: (data) => service.get('/v1/check', data)",
+	)
+}
+
+func TestEmptyRequestTypeNotImported(t *testing.T) {
+	// 没有请求参数的方法不引用请求类型，也不生成 XxxQuery
+	generated := mustRunPlugin(t, "output_paths=ts,split_query_types=true", healthFile())
+	ts := generatedFile(t, generated, "ts/healthApi.ts")
+	assertContains(t, ts, "export type CheckQuery = Pick<CheckReq, 'component'>;", "GetHealth: (): Promise<Status> =>")
+	assertNotContains(t, ts, "NoArgs", "GetHealthQuery")
+
+	// connect 客户端类型仍以请求类型声明参数
+	generated = mustRunPlugin(t, "output_paths=ts,protocol=connect", healthFile())
+	assertContains(t, generatedFile(t, generated, "ts/healthApi.ts"), "import type { CheckReq, NoArgs, Status }", "reset(request: NoArgs,")
+}
+
+func TestEmptyRequestOpsMap(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,emit_ops_map=true", healthFile())
+	assertContains(t, generatedFile(t, generated, "ts/healthApi.ts"),
+		"  GetHealth: { req: void; res: Status };",
+		"  Reset: { req: void; res: Empty };",
+		"  Check: { req: CheckReq; res: Status };",
+	)
+}
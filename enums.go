@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// collectEnums 收集方法请求/响应消息（含嵌套消息字段）中用到的枚举，按 ts-proto 名称排序去重
+func collectEnums(methods []MethodInfo) []*protogen.Enum {
+	var messages []*protogen.Message
+	for _, m := range methods {
+		messages = append(messages, m.Input, m.Output)
+	}
+	return collectEnumsFrom(messages)
+}
+
+// collectRequestEnums 只收集请求消息（含嵌套消息字段）中用到的枚举
+func collectRequestEnums(methods []MethodInfo) []*protogen.Enum {
+	var messages []*protogen.Message
+	for _, m := range methods {
+		messages = append(messages, m.Input)
+	}
+	return collectEnumsFrom(messages)
+}
+
+// collectEnumsFrom 递归收集消息字段中用到的枚举，按 ts-proto 名称排序去重
+func collectEnumsFrom(messages []*protogen.Message) []*protogen.Enum {
+	seenMessages := make(map[string]bool)
+	seenEnums := make(map[string]*protogen.Enum)
+
+	var walk func(msg *protogen.Message)
+	walk = func(msg *protogen.Message) {
+		// 不进入 google.protobuf 下的 well-known types（如 Struct 中的 NullValue）
+		if msg == nil || msg.Desc.ParentFile().Package() == "google.protobuf" || seenMessages[string(msg.Desc.FullName())] {
+			return
+		}
+		seenMessages[string(msg.Desc.FullName())] = true
+		for _, field := range msg.Fields {
+			if field.Enum != nil {
+				seenEnums[string(field.Enum.Desc.FullName())] = field.Enum
+			}
+			walk(field.Message)
+		}
+	}
+	for _, msg := range messages {
+		walk(msg)
+	}
+
+	enums := make([]*protogen.Enum, 0, len(seenEnums))
+	for _, e := range seenEnums {
+		enums = append(enums, e)
+	}
+	sort.Slice(enums, func(i, j int) bool {
+		return enumTypeName(enums[i]) < enumTypeName(enums[j])
+	})
+	return enums
+}
+
+// enumTypeName 返回枚举在 ts-proto 中的类型名：嵌套枚举以 _ 连接外层消息名（例如：Order.Status -> Order_Status）
+func enumTypeName(e *protogen.Enum) string {
+	fullName := string(e.Desc.FullName())
+	pkg := string(e.Desc.ParentFile().Package())
+	if pkg != "" {
+		fullName = strings.TrimPrefix(fullName, pkg+".")
+	}
+	return strings.ReplaceAll(fullName, ".", "_")
+}
+
+// enumHelperPrefix 返回枚举辅助函数名前缀（例如：Order_Status -> orderStatus）
+func enumHelperPrefix(e *protogen.Enum) string {
+	return toCamelCase(strings.ReplaceAll(enumTypeName(e), "_", ""))
+}
+
+// writeEnumHelpers 为每个枚举生成数值与名称的互转函数，兼容 proto JSON 中枚举的两种表示
+// typed 为 true 时生成 TS 类型标注（名称联合类型 XxxName）
+func writeEnumHelpers(buf *bytes.Buffer, enums []*protogen.Enum, typed bool) {
+	for _, e := range enums {
+		prefix := enumHelperPrefix(e)
+		nameType := enumTypeName(e) + "Name"
+
+		var quotedNames, numberEntries, nameEntries []string
+		seen := make(map[int32]bool)
+		for _, v := range e.Values {
+			name := string(v.Desc.Name())
+			n := strconv.Itoa(int(v.Desc.Number()))
+			quotedNames = append(quotedNames, "'"+name+"'")
+			nameEntries = append(nameEntries, name+": "+n)
+			// allow_alias 时同一数值只保留第一个名称
+			if !seen[int32(v.Desc.Number())] {
+				seen[int32(v.Desc.Number())] = true
+				numberEntries = append(numberEntries, n+": '"+name+"'")
+			}
+		}
+
+		if typed {
+			buf.WriteString("export type " + nameType + " = " + strings.Join(quotedNames, " | ") + ";\n")
+			buf.WriteString("const " + prefix + "Names: Record<number, " + nameType + "> = { " + strings.Join(numberEntries, ", ") + " };\n")
+			buf.WriteString("const " + prefix + "Numbers: Record<" + nameType + ", number> = { " + strings.Join(nameEntries, ", ") + " };\n")
+			buf.WriteString("export const " + prefix + "FromNumber = (n: number): " + nameType + " | undefined => " + prefix + "Names[n];\n")
+			buf.WriteString("export const " + prefix + "ToNumber = (s: " + nameType + " | number): number =>\n")
+			buf.WriteString("  typeof s === 'number' ? s : " + prefix + "Numbers[s];\n\n")
+		} else {
+			buf.WriteString("const " + prefix + "Names = { " + strings.Join(numberEntries, ", ") + " };\n")
+			buf.WriteString("const " + prefix + "Numbers = { " + strings.Join(nameEntries, ", ") + " };\n")
+			buf.WriteString("export const " + prefix + "FromNumber = (n) => " + prefix + "Names[n];\n")
+			buf.WriteString("export const " + prefix + "ToNumber = (s) => (typeof s === 'number' ? s : " + prefix + "Numbers[s]);\n\n")
+		}
+	}
+}
+
+// writeEnumConstants 为枚举生成值常量（如 OrderStatus.ACTIVE），值为枚举数值，可直接赋给 ts-proto 的枚举类型字段
+// 常量以枚举名在服务模块中导出，以命名空间导入时即为 GoodsApi.OrderStatus.ACTIVE（import * as GoodsApi from './goodsApi'）；
+// 不放进 API 对象，避免 hooks、emit_ops_map、bundle_dts 等按方法遍历 API 对象的输出把常量当作方法
+// typed 为 true 时追加 as const，indent 为每层缩进，trailingComma 为 trailing_comma 配置
+func writeEnumConstants(buf *bytes.Buffer, enums []*protogen.Enum, typed bool, indent, trailingComma string) {
+	for _, e := range enums {
+		buf.WriteString("export const ")
+		buf.WriteString(enumTypeName(e))
+		buf.WriteString(" = {\n")
+		keys := enumValueKeys(e)
+		for i, v := range e.Values {
+			buf.WriteString(indent)
+			buf.WriteString(keys[i])
+			buf.WriteString(": ")
+			buf.WriteString(strconv.Itoa(int(v.Desc.Number())))
+			buf.WriteString(memberEnd(i == len(e.Values)-1, trailingComma))
+		}
+		buf.WriteString("}")
+		if typed {
+			buf.WriteString(" as const")
+		}
+		buf.WriteString(";\n\n")
+	}
+}
+
+// enumValueKeys 返回枚举值常量的键名：按 proto 风格指南以枚举名的大写下划线形式为前缀时去掉前缀（ORDER_STATUS_ACTIVE -> ACTIVE）；
+// 有值不带前缀、去掉后为空、以数字开头或出现重名时保留完整的值名
+func enumValueKeys(e *protogen.Enum) []string {
+	prefix := upperSnake(string(e.Desc.Name())) + "_"
+	keys := make([]string, len(e.Values))
+	seen := make(map[string]bool)
+	for i, v := range e.Values {
+		key := strings.TrimPrefix(string(v.Desc.Name()), prefix)
+		if key == string(v.Desc.Name()) || key == "" || (key[0] >= '0' && key[0] <= '9') || seen[key] {
+			for j, v := range e.Values {
+				keys[j] = string(v.Desc.Name())
+			}
+			return keys
+		}
+		seen[key] = true
+		keys[i] = key
+	}
+	return keys
+}
+
+// upperSnake 将 PascalCase 名称转为大写下划线形式（例如：OrderStatus -> ORDER_STATUS，HTTPMethod -> HTTP_METHOD）
+func upperSnake(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			prev := rune(name[i-1])
+			nextLower := i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z'
+			if (prev >= 'a' && prev <= 'z') || (prev >= '0' && prev <= '9') || (prev >= 'A' && prev <= 'Z' && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
+
+// enumValueLabel 返回枚举值的显示文本：前置注释（多行时取第一行），其次行尾注释，都没有时为值名
+func enumValueLabel(v *protogen.EnumValue) string {
+	for _, comments := range []protogen.Comments{v.Comments.Leading, v.Comments.Trailing} {
+		if text := strings.TrimSpace(string(comments)); text != "" {
+			return strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+		}
+	}
+	return string(v.Desc.Name())
+}
+
+// writeEnumLabels 为每个枚举生成数值到显示文本的映射（如 orderStatusLabels），便于渲染下拉框等
+// allow_alias 时同一数值只保留第一个值；typed 为 true 时标注为 Record<number, string>，trailingComma 为 trailing_comma 配置
+func writeEnumLabels(buf *bytes.Buffer, enums []*protogen.Enum, typed bool, indent, trailingComma string) {
+	for _, e := range enums {
+		buf.WriteString("export const ")
+		buf.WriteString(enumHelperPrefix(e))
+		buf.WriteString("Labels")
+		if typed {
+			buf.WriteString(": Record<number, string>")
+		}
+		buf.WriteString(" = {\n")
+		seen := make(map[int32]bool)
+		var members []string
+		for _, v := range e.Values {
+			if seen[int32(v.Desc.Number())] {
+				continue
+			}
+			seen[int32(v.Desc.Number())] = true
+			members = append(members, indent+strconv.Itoa(int(v.Desc.Number()))+": "+singleQuote(enumValueLabel(v)))
+		}
+		for i, member := range members {
+			buf.WriteString(member)
+			buf.WriteString(memberEnd(i == len(members)-1, trailingComma))
+		}
+		buf.WriteString("};\n\n")
+	}
+}
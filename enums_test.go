@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestInlineRequestEnumsStripPrefix(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js,inline_request_enums=true", statusFile())
+	assertContains(t, generatedFile(t, generated, "ts/statusApi.ts"),
+		"export const OrderStatus = {\n  UNSPECIFIED: 0,\n  ACTIVE: 1,\n  CLOSED: 2,\n} as const;",
+	)
+	js := generatedFile(t, generated, "js/statusApi.js")
+	assertContains(t, js, "export const OrderStatus = {\n    UNSPECIFIED: 0,\n    ACTIVE: 1,\n    CLOSED: 2,\n};")
+	// 常量不放进 API 对象
+	assertNotContains(t, js[strings.Index(js, "export const statusApi"):], "OrderStatus")
+}
+
+func TestInlineRequestEnumsKeepFullNames(t *testing.T) {
+	file := protoFile("shop/v1/color.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("PaintReq", protoField("color", 1, typeEnum, ".shop.v1.Color"), protoField("size", 2, typeEnum, ".shop.v1.Size")),
+		},
+		protoService("PaintService", protoMethod("Paint", ".shop.v1.PaintReq", ".google.protobuf.Empty", httpPost("/v1/paint", "*"))),
+	)
+	// Color 有值不带前缀；Size 去掉前缀后以数字开头
+	withEnums(file, protoEnum("Color", "COLOR_UNSPECIFIED", "RED"), protoEnum("Size", "SIZE_UNSPECIFIED", "SIZE_2XL"))
+	generated := mustRunPlugin(t, "output_paths=ts,inline_request_enums=true", file)
+	assertContains(t, generatedFile(t, generated, "ts/paintApi.ts"),
+		"export const Color = {\n  COLOR_UNSPECIFIED: 0,\n  RED: 1,\n} as const;",
+		"export const Size = {\n  SIZE_UNSPECIFIED: 0,\n  SIZE_2XL: 1,\n} as const;",
+	)
+}
+
+func TestInlineRequestEnumsNamespaceImport(t *testing.T) {
+	node := requireNode(t)
+	generated := mustRunPlugin(t, "output_paths_js=js,inline_request_enums=true", statusFile())
+	dir := t.TempDir()
+	files := map[string]string{
+		"package.json": `{"type": "module"}`,
+		"api.js":       "export default {};\n",
+		"statusApi.js": generatedFile(t, generated, "js/statusApi.js"),
+		// 请求中的写法：以命名空间导入服务模块后按 StatusApi.OrderStatus.ACTIVE 取值
+		"main.js": "import * as StatusApi from './statusApi.js';\nconsole.log(StatusApi.OrderStatus.ACTIVE);\n",
+	}
+	for name, content := range files {
+		content = strings.ReplaceAll(content, "from './api';", "from './api.js';")
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, err := exec.Command(node, filepath.Join(dir, "main.js")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("node: %v\n%s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); got != "1" {
+		t.Errorf("StatusApi.OrderStatus.ACTIVE = %s, want 1", got)
+	}
+}
+
+func TestUpperSnake(t *testing.T) {
+	for name, want := range map[string]string{
+		"OrderStatus": "ORDER_STATUS",
+		"Status":      "STATUS",
+		"HTTPMethod":  "HTTP_METHOD",
+		"V2Kind":      "V2_KIND",
+		"Order_Type":  "ORDER_TYPE",
+	} {
+		if got := upperSnake(name); got != want {
+			t.Errorf("upperSnake(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
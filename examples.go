@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// exampleEntry 示例对象中的一个字段
+type exampleEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// exampleObject 有序的示例对象（保持 proto 字段顺序，生成结果稳定）
+type exampleObject []exampleEntry
+
+// buildExample 根据字段注释中的 @example 构造消息的示例对象，未标注的字段使用零值，嵌套消息递归展开
+// timestampType 为 timestamp_type，决定 Timestamp 零值的表示
+func buildExample(msg *protogen.Message, useJSON bool, timestampType string) exampleObject {
+	// Struct / Value / ListValue 作为请求/响应时是任意 JSON，不展开 ts-proto 的包装字段
+	if msg != nil && isJSONWellKnown(msg) {
+		return exampleObject{}
+	}
+	return buildExampleSeen(msg, useJSON, timestampType, map[string]bool{})
+}
+
+func buildExampleSeen(msg *protogen.Message, useJSON bool, timestampType string, seen map[string]bool) exampleObject {
+	obj := exampleObject{}
+	if msg == nil || seen[string(msg.Desc.FullName())] {
+		return obj
+	}
+	seen[string(msg.Desc.FullName())] = true
+	defer delete(seen, string(msg.Desc.FullName()))
+
+	for _, field := range msg.Fields {
+		obj = append(obj, exampleEntry{Key: fieldKey(field, useJSON), Value: fieldExample(field, useJSON, timestampType, seen)})
+	}
+	return obj
+}
+
+// fieldExample 返回字段的示例值：优先使用注释中的 @example，否则使用零值
+func fieldExample(field *protogen.Field, useJSON bool, timestampType string, seen map[string]bool) interface{} {
+	if raw, ok := exampleFromComments(field); ok {
+		v := parseExampleValue(raw)
+		switch {
+		case field.Desc.IsMap():
+			if _, isObj := v.(exampleObject); isObj {
+				return v
+			}
+		case field.Desc.IsList():
+			if list, isList := v.([]interface{}); isList {
+				return list
+			}
+			return []interface{}{coerceExample(field, v, raw, timestampType)}
+		default:
+			return coerceExample(field, v, raw, timestampType)
+		}
+	}
+	switch {
+	case field.Desc.IsMap():
+		return exampleObject{}
+	case field.Desc.IsList():
+		return []interface{}{}
+	}
+	return zeroExample(field, useJSON, timestampType, seen)
+}
+
+// coerceExample 字符串类字段（string/bytes）的示例不是字符串时，按原文作为字符串，枚举字段的示例转为数值；
+// timestamp_type=Date 时 Timestamp 字段的字符串示例转为 new Date('...')
+func coerceExample(field *protogen.Field, v interface{}, raw, timestampType string) interface{} {
+	if s, isStr := v.(string); isStr && timestampType == "Date" && isWellKnown(field.Message, "Timestamp") {
+		return rawExpr("new Date(" + singleQuote(s) + ")")
+	}
+	switch field.Desc.Kind() {
+	case protoreflect.EnumKind:
+		return enumExample(field, v, raw)
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		if _, isStr := v.(string); !isStr {
+			return raw
+		}
+	}
+	return v
+}
+
+// enumExample 返回枚举字段的示例值：与 ts-proto 的数值枚举一致，@example 为枚举值名时转为对应的数值，
+// 为数值时原样使用；无法识别的名称按原文作为字符串
+func enumExample(field *protogen.Field, v interface{}, raw string) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		return t
+	case string:
+		raw = t
+	}
+	if value := field.Enum.Desc.Values().ByName(protoreflect.Name(raw)); value != nil {
+		return json.Number(strconv.Itoa(int(value.Number())))
+	}
+	return raw
+}
+
+// zeroExample 返回单个字段的零值示例（与生成的类型一致：枚举为数值，其余与 proto JSON 表示一致）
+func zeroExample(field *protogen.Field, useJSON bool, timestampType string, seen map[string]bool) interface{} {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return false
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return ""
+	case protoreflect.EnumKind:
+		// ts-proto 的枚举为数值，零值取第一个枚举值（proto3 中为 0）
+		if values := field.Enum.Values; len(values) > 0 {
+			return json.Number(strconv.Itoa(int(values[0].Desc.Number())))
+		}
+		return json.Number("0")
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageZeroExample(field.Message, useJSON, timestampType, seen)
+	}
+	return json.Number("0")
+}
+
+// messageZeroExample 返回消息字段的零值示例，well-known types 使用其 JSON 表示（timestamp_type=Date 时 Timestamp 为 new Date(0)）
+func messageZeroExample(msg *protogen.Message, useJSON bool, timestampType string, seen map[string]bool) interface{} {
+	if msg.Desc.ParentFile().Package() == "google.protobuf" {
+		switch msg.Desc.Name() {
+		case "Timestamp":
+			if timestampType == "Date" {
+				return rawExpr("new Date(0)")
+			}
+			return "1970-01-01T00:00:00Z"
+		case "Duration":
+			return "0s"
+		case "FieldMask":
+			return ""
+		case "ListValue":
+			return []interface{}{}
+		case "Value", "DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value",
+			"UInt32Value", "BoolValue", "StringValue", "BytesValue":
+			return nil
+		}
+		return exampleObject{}
+	}
+	return buildExampleSeen(msg, useJSON, timestampType, seen)
+}
+
+// exampleFromComments 从字段的前置/行尾注释中提取 @example 后的内容（到行尾）
+func exampleFromComments(field *protogen.Field) (string, bool) {
+	for _, comments := range []protogen.Comments{field.Comments.Leading, field.Comments.Trailing} {
+		for _, line := range strings.Split(string(comments), "\n") {
+			if i := strings.Index(line, "@example"); i >= 0 {
+				return strings.TrimSpace(line[i+len("@example"):]), true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseExampleValue 将 @example 内容按 JSON 解析，解析失败时按原文作为字符串
+func parseExampleValue(raw string) interface{} {
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return raw
+	}
+	return normalizeExample(v)
+}
+
+// normalizeExample 将 JSON 解析出的 map 转为按键排序的 exampleObject
+func normalizeExample(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		obj := exampleObject{}
+		for _, k := range keys {
+			obj = append(obj, exampleEntry{Key: k, Value: normalizeExample(t[k])})
+		}
+		return obj
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeExample(t[i])
+		}
+		return t
+	}
+	return v
+}
+
+// renderExample 将示例值渲染为 JS 字面量，对象逐字段换行，indent 为当前行缩进，step 为每层缩进，
+// trailingComma 为 trailing_comma 配置（决定对象末字段后是否带逗号）
+func renderExample(v interface{}, indent, step, trailingComma string) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case rawExpr:
+		return string(t)
+	case bool:
+		return strconv.FormatBool(t)
+	case json.Number:
+		return t.String()
+	case string:
+		return singleQuote(t)
+	case []interface{}:
+		items := make([]string, len(t))
+		for i, item := range t {
+			items[i] = renderExample(item, indent, step, trailingComma)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case exampleObject:
+		if len(t) == 0 {
+			return "{}"
+		}
+		var b strings.Builder
+		b.WriteString("{\n")
+		for i, entry := range t {
+			b.WriteString(indent + step)
+			b.WriteString(exampleKey(entry.Key))
+			b.WriteString(": ")
+			b.WriteString(renderExample(entry.Value, indent+step, step, trailingComma))
+			b.WriteString(memberEnd(i == len(t)-1, trailingComma))
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	}
+	return "null"
+}
+
+// exampleKey 合法标识符直接作为对象键，否则加引号
+func exampleKey(key string) string {
+	for i, r := range key {
+		isLetter := r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !isLetter && (i == 0 || r < '0' || r > '9') {
+			return singleQuote(key)
+		}
+	}
+	if key == "" {
+		return "''"
+	}
+	return key
+}
+
+// singleQuote 将字符串渲染为单引号 JS 字符串字面量
+func singleQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`)
+	return "'" + r.Replace(s) + "'"
+}
+
+// writeExamples 生成每个方法的请求/响应示例常量（如 OrderExamples），供 mock、文档和表单默认值使用
+// 示例不挂在 API 对象的方法上（如 orderApi.CreateOrder.example）：TS 不允许给对象字面量中的箭头函数追加属性，
+// 改为 Object.assign 会改变方法类型，影响 hooks、bundle_dts 等依赖方法签名的输出，因此按方法名单独导出
+// indent 为每层缩进（TS 为两个空格，JS 为四个空格）；verb_response 为 void 的方法不生成 response
+func writeExamples(buf *bytes.Buffer, data ServiceInfo, indent string) {
+	if !data.EmitExamples {
+		return
+	}
+	buf.WriteString("export const ")
+	buf.WriteString(data.ServiceName)
+	buf.WriteString("Examples = {\n")
+	for i, method := range data.Methods {
+		hasResponse := data.VerbResponses[method.HttpMethod] != "void"
+		buf.WriteString(indent)
+		buf.WriteString(method.MethodName)
+		buf.WriteString(": {\n")
+		buf.WriteString(indent + indent)
+		buf.WriteString("request: ")
+		buf.WriteString(renderExample(method.RequestExample, indent+indent, indent, data.TrailingComma))
+		buf.WriteString(memberEnd(!hasResponse, data.TrailingComma))
+		if hasResponse {
+			buf.WriteString(indent + indent)
+			buf.WriteString("response: ")
+			buf.WriteString(renderExample(method.ResponseExample, indent+indent, indent, data.TrailingComma))
+			buf.WriteString(memberEnd(true, data.TrailingComma))
+		}
+		buf.WriteString(indent)
+		buf.WriteString("}")
+		buf.WriteString(memberEnd(i == len(data.Methods)-1, data.TrailingComma))
+	}
+	buf.WriteString("};\n\n")
+}
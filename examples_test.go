@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// statusFile 返回请求含三个枚举字段的订单状态服务：status 无示例，prev_status 的示例为枚举值名，next_status 的示例为数值
+func statusFile() *descriptorpb.FileDescriptorProto {
+	file := withEnums(protoFile("shop/v1/status.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("SetStatusReq",
+				protoField("order_id", 1, typeString, ""),
+				protoField("status", 2, typeEnum, ".shop.v1.OrderStatus"),
+				protoField("prev_status", 3, typeEnum, ".shop.v1.OrderStatus"),
+				protoField("next_status", 4, typeEnum, ".shop.v1.OrderStatus"),
+			),
+		},
+		protoService("StatusService",
+			protoMethod("SetStatus", ".shop.v1.SetStatusReq", ".google.protobuf.Empty", httpPost("/v1/orders/{order_id}/status", "*")),
+		),
+	), protoEnum("OrderStatus", "ORDER_STATUS_UNSPECIFIED", "ORDER_STATUS_ACTIVE", "ORDER_STATUS_CLOSED"))
+	withComments(file, []int32{4, 0, 2, 2}, "", " @example ORDER_STATUS_ACTIVE\n")
+	return withComments(file, []int32{4, 0, 2, 3}, "", " @example 2\n")
+}
+
+func TestExamplesUseNumericEnums(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,emit_examples=true,emit_zod=true", statusFile())
+	code := generatedFile(t, generated, "ts/statusApi.ts")
+	// 示例按方法名导出为 StatusExamples.SetStatus.request，枚举与 zod schema、ts-proto 一致为数值
+	assertContains(t, code,
+		"export const StatusExamples = {\n  SetStatus: {\n    request: {\n      orderId: '',\n      status: 0,\n      prevStatus: 1,\n      nextStatus: 2,\n    },\n",
+		"  status: z.number().int(),",
+	)
+	assertNotContains(t, code, "'ORDER_STATUS_")
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+// namedExportReserved 不能用作具名导出函数名或参数名的标识符：JS 保留字及生成文件中已有的模块级名称
+var namedExportReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "implements": true, "import": true, "in": true, "instanceof": true, "interface": true,
+	"let": true, "new": true, "null": true, "package": true, "private": true, "protected": true,
+	"public": true, "return": true, "static": true, "super": true, "switch": true, "this": true,
+	"throw": true, "true": true, "try": true, "typeof": true, "var": true, "void": true,
+	"while": true, "with": true, "yield": true, "await": true,
+	"service": true, "request": true, "z": true, "configure": true, "call": true, "cached": true,
+	"getCache": true, "clearCache": true, "abortAll": true, "trackAbort": true, "pendingControllers": true,
+	"renameKeys": true, "withRetry": true, "useQuery": true, "useMutation": true, "useInfiniteQuery": true,
+	"useRequest": true, "ref": true, "shallowRef": true, "client": true, "setClient": true,
+	"useSWR": true, "useSWRMutation": true,
+}
+
+// jsIdentifier 合法的 JS 标识符（仅 ASCII）
+var jsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// namedExportName 返回 export_style=named 时方法的导出函数名（例如：GetGoods -> getGoods）
+// 与保留字或模块级名称冲突时追加 _（例如：Delete -> delete_）
+func namedExportName(methodName string) string {
+	name := toCamelCase(methodName)
+	if namedExportReserved[name] {
+		name += "_"
+	}
+	return name
+}
+
+// validateParamName 校验 param_name：需为合法的 JS 标识符，且不能与保留字、生成文件中的模块级名称或 compat_args 的 args 冲突
+func validateParamName(value string) error {
+	if !jsIdentifier.MatchString(value) || namedExportReserved[value] || value == "args" {
+		return fmt.Errorf("param_name 需为合法的 JS 标识符，且不能是保留字或生成代码中已使用的名称: %s", value)
+	}
+	return nil
+}
+
+// declPrefix 返回方法声明的开头：对象成员为 "  Name: "，具名导出为 "export const name = "
+func declPrefix(name, indent string, named bool) string {
+	if named {
+		return "export const " + name + " = "
+	}
+	return indent + name + ": "
+}
+
+// writeApiObject 写入 API 对象（TS、JS 共用，typed 为 true 时为 TS，indent 为每层缩进）
+// export_style=named 时先将每个方法具名导出，便于 tree-shaking，再以 { 方法名: 函数 } 组装为同名对象，保持原有调用方式
+func writeApiObject(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	var members []string
+	if data.ExportStyle == "named" {
+		for _, method := range data.Methods {
+			name := namedExportName(method.MethodName)
+			var decls []string
+			if typed {
+				decls = typeScriptDecls(data, method, name, "", indent, true)
+			} else {
+				decls = javaScriptDecls(data, method, name, "", indent, true)
+			}
+			for _, decl := range decls {
+				buf.WriteString(decl)
+				buf.WriteString(";\n\n")
+			}
+			members = append(members, indent+method.MethodName+": "+name)
+			if data.EmitPathBuilders {
+				members = append(members, indent+method.MethodName+"Path: "+name+"Path")
+			}
+		}
+	} else {
+		// 每个成员先单独渲染，最后以逗号连接
+		for _, method := range data.Methods {
+			if typed {
+				members = append(members, typeScriptMembers(data, method, method.MethodName)...)
+			} else {
+				members = append(members, javaScriptMembers(data, method, method.MethodName)...)
+			}
+		}
+	}
+
+	writeLineComment(buf, data.Comment)
+	writeObjectDeprecation(buf, data)
+	buf.WriteString("export const ")
+	buf.WriteString(data.ApiFileName)
+	buf.WriteString(" = {\n")
+	buf.WriteString(joinMembers(members, data.TrailingComma))
+	buf.WriteString("\n")
+	buf.WriteString("};\n\n")
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// validateClient 校验 client 的取值
+func validateClient(value string) error {
+	if value != "service" && value != "fetch" {
+		return fmt.Errorf("client 只支持 service、fetch: %s", value)
+	}
+	return nil
+}
+
+// writeServiceImport 写入 service 的默认导入；client=fetch 或 protocol=connect 时不依赖 service，不写导入
+func writeServiceImport(buf *bytes.Buffer, data ServiceInfo, serviceImport string) {
+	if data.Client == "fetch" || data.Protocol == "connect" {
+		return
+	}
+	buf.WriteString("import service from '")
+	buf.WriteString(serviceImport)
+	buf.WriteString("';\n")
+}
+
+// fetchCall 返回 client=fetch 时的请求调用：request<T>('METHOD', path, data, options)，body 指定字段时其余字段作为第五个参数 queryParams
+// verb_response 为 data 时响应体即为 { data }，T 相应包裹一层
+func fetchCall(data ServiceInfo, method MethodInfo, path string, typed bool) string {
+	call := "request"
+	if typed {
+		result := responseType(data, method)
+		switch data.VerbResponses[method.HttpMethod] {
+		case "void":
+			result = "unknown"
+		case "data":
+			result = "{ data: " + result + " }"
+		}
+		call += "<" + result + ">"
+	}
+	args := requestArgs(data, method)
+	// body 指定字段时其余字段作为 queryParams 传入，没有请求选项时以 undefined 占位
+	if query := bodyQuery(data, method); query != "" {
+		options := strings.TrimPrefix(configOptions(data), ", ")
+		if options == "" {
+			options = "undefined"
+		}
+		args = requestData(data, method) + ", " + options + ", " + query
+	}
+	return call + "('" + strings.ToUpper(method.HttpMethod) + "', " + strings.TrimSuffix(path+", "+args, ", ") + ")"
+}
+
+// writeFetchHelper client=fetch 时生成模块内的 request 辅助函数：基于 fetch 发送 JSON 请求，
+// GET/DELETE 的数据作为查询参数（数组展开为同名多值），其余方法作为 JSON 请求体，有 body 指定字段的方法时另接收 queryParams 作为查询参数；
+// 非 2xx 响应抛出带 status 的 Error（与 retry 的状态码判断一致），204 返回 undefined
+// 请求地址为 fetch_base_url 加路径；第四个参数为 RequestInit，emit_configure 的 headers 与 emit_abort_all 的 signal 经此传入
+// typed 为 true 时生成泛型与类型标注，indent 为每层缩进
+func writeFetchHelper(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if data.Client != "fetch" {
+		return
+	}
+	in1, in2, in3, in4 := indent, indent+indent, indent+indent+indent, indent+indent+indent+indent
+	buf.WriteString("const BASE_URL = ")
+	buf.WriteString(singleQuote(data.FetchBaseURL))
+	buf.WriteString(";\n\n")
+	// body 指定字段的方法其余字段作为第五个参数 queryParams 拼到查询串
+	query := hasBodyQuery(data)
+	if typed && query {
+		buf.WriteString("const request = async <T>(method: string, path: string, body?: object, init: RequestInit = {}, queryParams?: object): Promise<T> => {\n")
+		buf.WriteString(in1 + "const options: RequestInit = { ...init, method, headers: { 'Content-Type': 'application/json', ...(init.headers as Record<string, string>) } };\n")
+	} else if typed {
+		buf.WriteString("const request = async <T>(method: string, path: string, body?: object, init: RequestInit = {}): Promise<T> => {\n")
+		buf.WriteString(in1 + "const options: RequestInit = { ...init, method, headers: { 'Content-Type': 'application/json', ...(init.headers as Record<string, string>) } };\n")
+	} else if query {
+		buf.WriteString("const request = async (method, path, body, init = {}, queryParams) => {\n")
+		buf.WriteString(in1 + "const options = { ...init, method, headers: { 'Content-Type': 'application/json', ...init.headers } };\n")
+	} else {
+		buf.WriteString("const request = async (method, path, body, init = {}) => {\n")
+		buf.WriteString(in1 + "const options = { ...init, method, headers: { 'Content-Type': 'application/json', ...init.headers } };\n")
+	}
+	buf.WriteString(in1 + "let url = BASE_URL + path;\n")
+	search := "body"
+	if query {
+		buf.WriteString(in1 + "const bodyless = method === 'GET' || method === 'DELETE';\n")
+		buf.WriteString(in1 + "const search = bodyless ? body : queryParams;\n")
+		buf.WriteString(in1 + "if (search !== undefined) {\n")
+		search = "search"
+	} else {
+		buf.WriteString(in1 + "if (body !== undefined && (method === 'GET' || method === 'DELETE')) {\n")
+	}
+	buf.WriteString(in2 + "const params = new URLSearchParams();\n")
+	buf.WriteString(in2 + "Object.entries(" + search + ").forEach(([key, value]) => {\n")
+	buf.WriteString(in3 + "if (value === undefined || value === null) {\n")
+	buf.WriteString(in4 + "return;\n")
+	buf.WriteString(in3 + "}\n")
+	buf.WriteString(in3 + "(Array.isArray(value) ? value : [value]).forEach((item) => params.append(key, String(item)));\n")
+	buf.WriteString(in2 + "});\n")
+	buf.WriteString(in2 + "const query = params.toString();\n")
+	buf.WriteString(in2 + "if (query) {\n")
+	buf.WriteString(in3 + "url += (url.includes('?') ? '&' : '?') + query;\n")
+	buf.WriteString(in2 + "}\n")
+	if query {
+		buf.WriteString(in1 + "}\n")
+		buf.WriteString(in1 + "if (body !== undefined && !bodyless) {\n")
+	} else {
+		buf.WriteString(in1 + "} else if (body !== undefined) {\n")
+	}
+	buf.WriteString(in2 + "options.body = JSON.stringify(body);\n")
+	buf.WriteString(in1 + "}\n")
+	buf.WriteString(in1 + "const res = await fetch(url, options);\n")
+	buf.WriteString(in1 + "if (!res.ok) {\n")
+	buf.WriteString(in2 + "throw Object.assign(new Error(`${method} ${path} failed: ${res.status}`), { status: res.status });\n")
+	buf.WriteString(in1 + "}\n")
+	if typed {
+		buf.WriteString(in1 + "return (res.status === 204 ? undefined : await res.json()) as T;\n")
+	} else {
+		buf.WriteString(in1 + "return res.status === 204 ? undefined : res.json();\n")
+	}
+	buf.WriteString("};\n\n")
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"path"
+)
+
+// nameFilter 按 glob 模式（path.Match 语法，如 Get*、*Internal）过滤名称：
+// Include 非空时只保留匹配其中任一模式的名称，再去掉匹配 Exclude 中任一模式的名称
+type nameFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// parsePatterns 解析 ; 分隔的 glob 模式列表（, 已用于分隔参数），模式不合法时返回错误
+func parsePatterns(option, value string) ([]string, error) {
+	patterns := parseSuffixList(value)
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s 的模式不合法: %s", option, pattern)
+		}
+	}
+	return patterns, nil
+}
+
+// matchAny 判断名称是否匹配任一模式
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// allows 判断名称是否通过过滤
+func (f nameFilter) allows(name string) bool {
+	if len(f.Include) > 0 && !matchAny(f.Include, name) {
+		return false
+	}
+	return !matchAny(f.Exclude, name)
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// 测试用 proto 描述的字段类型
+const (
+	typeString  = descriptorpb.FieldDescriptorProto_TYPE_STRING
+	typeInt32   = descriptorpb.FieldDescriptorProto_TYPE_INT32
+	typeBool    = descriptorpb.FieldDescriptorProto_TYPE_BOOL
+	typeMessage = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	typeEnum    = descriptorpb.FieldDescriptorProto_TYPE_ENUM
+)
+
+// protoField 返回单值字段，typeName 为消息 / 枚举的全名（如 .shop.v1.Order），标量字段为空
+func protoField(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Type:     kind.Enum(),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		JsonName: proto.String(protoJSONName(name)),
+	}
+	if typeName != "" {
+		field.TypeName = proto.String(typeName)
+	}
+	return field
+}
+
+// repeatedField 返回 repeated 字段
+func repeatedField(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	field := protoField(name, number, kind, typeName)
+	field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	return field
+}
+
+// protoJSONName 按 protoc 的规则返回字段的 json_name（下划线后的字母大写）
+func protoJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// protoMessage 返回消息定义
+func protoMessage(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+}
+
+// httpGet 返回 GET 规则
+func httpGet(path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: path}}
+}
+
+// httpPost 返回 POST 规则，body 为 * 或请求消息的字段名
+func httpPost(path, body string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: path}, Body: body}
+}
+
+// httpDelete 返回 DELETE 规则
+func httpDelete(path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Delete{Delete: path}}
+}
+
+// httpCustom 返回 custom 规则，kind 为 HTTP 方法（如 HEAD、OPTIONS）
+func httpCustom(kind, path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Custom{Custom: &annotations.CustomHttpPattern{Kind: kind, Path: path}}}
+}
+
+// protoMethod 返回带 google.api.http 注解的方法，input / output 为消息全名
+func protoMethod(name, input, output string, rule *annotations.HttpRule) *descriptorpb.MethodDescriptorProto {
+	options := &descriptorpb.MethodOptions{}
+	proto.SetExtension(options, annotations.E_Http, rule)
+	return &descriptorpb.MethodDescriptorProto{
+		Name:       proto.String(name),
+		InputType:  proto.String(input),
+		OutputType: proto.String(output),
+		Options:    options,
+	}
+}
+
+// protoService 返回服务定义
+func protoService(name string, methods ...*descriptorpb.MethodDescriptorProto) *descriptorpb.ServiceDescriptorProto {
+	return &descriptorpb.ServiceDescriptorProto{Name: proto.String(name), Method: methods}
+}
+
+// protoFile 返回 proto3 文件，依赖 annotations、empty、timestamp、struct
+func protoFile(path, pkg string, messages []*descriptorpb.DescriptorProto, services ...*descriptorpb.ServiceDescriptorProto) *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(path),
+		Package: proto.String(pkg),
+		Syntax:  proto.String("proto3"),
+		Dependency: []string{
+			"google/api/annotations.proto",
+			"google/protobuf/empty.proto",
+			"google/protobuf/timestamp.proto",
+			"google/protobuf/struct.proto",
+		},
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/" + strings.ReplaceAll(pkg, ".", "/"))},
+		MessageType: messages,
+		Service:     services,
+	}
+}
+
+// withEnums 为文件加上顶层枚举定义
+func withEnums(file *descriptorpb.FileDescriptorProto, enums ...*descriptorpb.EnumDescriptorProto) *descriptorpb.FileDescriptorProto {
+	file.EnumType = append(file.EnumType, enums...)
+	return file
+}
+
+// protoEnum 返回枚举定义，values 依次编号为 0、1、2……
+func protoEnum(name string, values ...string) *descriptorpb.EnumDescriptorProto {
+	enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(name)}
+	for i, value := range values {
+		enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String(value), Number: proto.Int32(int32(i))})
+	}
+	return enum
+}
+
+// withComments 为文件加上源码注释，path 为 SourceCodeInfo 中的位置路径（如 4, 0, 2, 1 为第一个消息的第二个字段），
+// leading / trailing 为前置 / 行尾注释，为空时不设置
+func withComments(file *descriptorpb.FileDescriptorProto, path []int32, leading, trailing string) *descriptorpb.FileDescriptorProto {
+	if file.SourceCodeInfo == nil {
+		file.SourceCodeInfo = &descriptorpb.SourceCodeInfo{}
+	}
+	loc := &descriptorpb.SourceCodeInfo_Location{Path: path, Span: []int32{0, 0, 1}}
+	if leading != "" {
+		loc.LeadingComments = proto.String(leading)
+	}
+	if trailing != "" {
+		loc.TrailingComments = proto.String(trailing)
+	}
+	file.SourceCodeInfo.Location = append(file.SourceCodeInfo.Location, loc)
+	return file
+}
+
+// orderFile 返回包 pkg 下的订单服务：serviceName 的 GetOrder 以 GET /v1/<prefix>/orders/{order_id} 返回 Order，
+// CreateOrder 以 body: "order" POST，ListOrders 返回 items + next_page_token 的分页响应
+func orderFile(path, pkg, serviceName, prefix string) *descriptorpb.FileDescriptorProto {
+	order := "." + pkg + ".Order"
+	return protoFile(path, pkg,
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("Order",
+				protoField("order_id", 1, typeString, ""),
+				protoField("created_at", 2, typeMessage, ".google.protobuf.Timestamp"),
+			),
+			protoMessage("GetOrderReq", protoField("order_id", 1, typeString, "")),
+			protoMessage("CreateOrderReq",
+				protoField("shop_id", 1, typeString, ""),
+				protoField("order", 2, typeMessage, order),
+				protoField("remark", 3, typeString, ""),
+			),
+			protoMessage("ListOrdersReq",
+				protoField("page_size", 1, typeInt32, ""),
+				protoField("page_token", 2, typeString, ""),
+			),
+			protoMessage("ListOrdersResp",
+				repeatedField("items", 1, typeMessage, order),
+				protoField("next_page_token", 2, typeString, ""),
+			),
+		},
+		protoService(serviceName,
+			protoMethod("GetOrder", "."+pkg+".GetOrderReq", order, httpGet("/v1/"+prefix+"/orders/{order_id}")),
+			protoMethod("CreateOrder", "."+pkg+".CreateOrderReq", order, httpPost("/v1/"+prefix+"/shops/{shop_id}/orders", "order")),
+			protoMethod("ListOrders", "."+pkg+".ListOrdersReq", "."+pkg+".ListOrdersResp", httpGet("/v1/"+prefix+"/orders")),
+		),
+	)
+}
+
+// runPlugin 以 param 运行插件生成 files 中的服务，文件经 CodeGeneratorResponse 返回（自动加上 write_response=true），
+// 返回生成的文件路径 -> 内容；param 中的输出路径须为相对路径
+func runPlugin(param string, files ...*descriptorpb.FileDescriptorProto) (map[string]string, error) {
+	if param != "" {
+		param += ","
+	}
+	gen, err := execPlugin(param+"write_response=true", files...)
+	if err != nil {
+		return nil, err
+	}
+	generated := make(map[string]string)
+	for _, file := range gen.Response().GetFile() {
+		generated[file.GetName()] = file.GetContent()
+	}
+	return generated, nil
+}
+
+// execPlugin 以 param 原样运行插件生成 files 中的服务（依赖的 google/api、well-known types 自动加入请求）
+func execPlugin(param string, files ...*descriptorpb.FileDescriptorProto) (*protogen.Plugin, error) {
+	all := []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+		protodesc.ToFileDescriptorProto(annotations.File_google_api_http_proto),
+		protodesc.ToFileDescriptorProto(annotations.File_google_api_annotations_proto),
+		protodesc.ToFileDescriptorProto(emptypb.File_google_protobuf_empty_proto),
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		protodesc.ToFileDescriptorProto(structpb.File_google_protobuf_struct_proto),
+	}
+	var names []string
+	for _, file := range files {
+		all = append(all, file)
+		names = append(names, file.GetName())
+	}
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: names,
+		Parameter:      proto.String(param),
+		ProtoFile:      all,
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		return nil, err
+	}
+	return gen, generate(gen)
+}
+
+// mustRunPlugin 同 runPlugin，生成失败时终止测试
+func mustRunPlugin(t *testing.T, param string, files ...*descriptorpb.FileDescriptorProto) map[string]string {
+	t.Helper()
+	generated, err := runPlugin(param, files...)
+	if err != nil {
+		t.Fatalf("生成失败（%s）: %v", param, err)
+	}
+	return generated
+}
+
+// generatedFile 返回生成的文件内容，文件不存在时终止测试
+func generatedFile(t *testing.T, generated map[string]string, name string) string {
+	t.Helper()
+	code, ok := generated[name]
+	if !ok {
+		names := make([]string, 0, len(generated))
+		for n := range generated {
+			names = append(names, n)
+		}
+		t.Fatalf("未生成 %s，生成的文件: %v", name, names)
+	}
+	return code
+}
+
+// requireNode 返回 node 可执行文件路径，未安装 node 时跳过测试
+func requireNode(t *testing.T) string {
+	t.Helper()
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("未安装 node")
+	}
+	return node
+}
+
+// assertContains 断言生成的代码包含 want 中的每一段
+func assertContains(t *testing.T, code string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(code, w) {
+			t.Errorf("生成的代码缺少 %q:\n%s", w, code)
+		}
+	}
+}
+
+// assertNotContains 断言生成的代码不包含 unwanted 中的任何一段
+func assertNotContains(t *testing.T, code string, unwanted ...string) {
+	t.Helper()
+	for _, u := range unwanted {
+		if strings.Contains(code, u) {
+			t.Errorf("生成的代码不应包含 %q:\n%s", u, code)
+		}
+	}
+}
+
+// assertErrorContains 断言 err 非空且包含 want
+func assertErrorContains(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("期望错误包含 %q，实际没有错误", want)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("期望错误包含 %q，实际为: %v", want, err)
+	}
+}
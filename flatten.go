@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// flatFileName flatten 模式下汇总文件的文件名（不含扩展名）
+// 不使用 api，避免与默认的 service_import（./api）冲突
+const flatFileName = "flatApi"
+
+// flatMemberName 返回方法在扁平对象中的键：服务名前缀 + 方法名（例如：order + GetOrder -> orderGetOrder）
+func flatMemberName(svc *ServiceInfo, method MethodInfo) string {
+	return strings.TrimSuffix(svc.ApiFileName, "Api") + method.MethodName
+}
+
+// flatProblems 检查扁平对象中是否有重名的键
+func flatProblems(services []*ServiceInfo) []string {
+	var problems []string
+	owners := make(map[string]string)
+	for _, svc := range services {
+		for _, method := range svc.Methods {
+			name := flatMemberName(svc, method)
+			owner := svc.FullName + "." + method.MethodName
+			if prev, ok := owners[name]; ok {
+				problems = append(problems, fmt.Sprintf("汇总对象中 %s 与 %s 生成的方法名相同: %s", owner, prev, name))
+				continue
+			}
+			owners[name] = owner
+		}
+	}
+	return problems
+}
+
+// flatServices 返回扁平模式使用的服务数据副本
+// 不生成 split_query_types 的查询类型、merge_defaults 的默认值常量、emit_configure 的模块配置、body_key_case 的键名映射
+// 与 emit_abort_all 的请求登记（各服务的同名声明可能冲突），
+// 方法参数直接使用请求类型、直接发送 data
+func flatServices(services []*ServiceInfo) []ServiceInfo {
+	flat := derefServices(services)
+	for i := range flat {
+		flat[i].SplitQueryTypes = false
+		flat[i].MergeDefaults = false
+		flat[i].EmitConfigure = false
+		flat[i].BodyKeyCase = ""
+		flat[i].EmitAbortAll = false
+	}
+	return flat
+}
+
+// generateFlatTypeScript 生成 flatten 模式的 TS 文件：所有服务的方法汇总到一个名为 objectName 的对象中
+func generateFlatTypeScript(services []*ServiceInfo, serviceImport, objectName string) []byte {
+	var buf bytes.Buffer
+	writeHeader(&buf, aggregateHeader(*services[0]))
+	writeServiceImport(&buf, *services[0], serviceImport)
+	writeTypeImports(&buf, services[0].TypesImportPath, mergeTypeImports(services))
+	buf.WriteString("\n")
+	writePageType(&buf, derefServices(services)...)
+	writeStrictTypes(&buf, mergeStrictTypes(services))
+	writeFetchHelper(&buf, *services[0], true, "  ")
+	writeRetryHelper(&buf, *services[0], true, "  ")
+	writeCacheHelper(&buf, *services[0], true, "  ")
+
+	buf.WriteString("export const ")
+	buf.WriteString(objectName)
+	buf.WriteString(" = {\n")
+	var members []string
+	for _, svc := range flatServices(services) {
+		for _, method := range svc.Methods {
+			members = append(members, typeScriptMembers(svc, method, flatMemberName(&svc, method))...)
+		}
+	}
+	buf.WriteString(joinMembers(members, services[0].TrailingComma))
+	buf.WriteString("\n};\n\n")
+	buf.WriteString("export default ")
+	buf.WriteString(objectName)
+	buf.WriteString(";\n")
+	return buf.Bytes()
+}
+
+// generateFlatJavaScript 生成 flatten 模式的 JS 文件
+func generateFlatJavaScript(services []*ServiceInfo, serviceImport, objectName string) []byte {
+	var buf bytes.Buffer
+	writeHeader(&buf, aggregateHeader(*services[0]))
+	writeServiceImport(&buf, *services[0], serviceImport)
+	if buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	writeFetchHelper(&buf, *services[0], false, "    ")
+	writeRetryHelper(&buf, *services[0], false, "    ")
+	writeCacheHelper(&buf, *services[0], false, "    ")
+
+	buf.WriteString("export const ")
+	buf.WriteString(objectName)
+	buf.WriteString(" = {\n")
+	var members []string
+	for _, svc := range flatServices(services) {
+		for _, method := range svc.Methods {
+			members = append(members, javaScriptMembers(svc, method, flatMemberName(&svc, method))...)
+		}
+	}
+	buf.WriteString(joinMembers(members, services[0].TrailingComma))
+	buf.WriteString("\n};\n\n")
+	buf.WriteString("export default ")
+	buf.WriteString(objectName)
+	buf.WriteString(";\n")
+	return buf.Bytes()
+}
+
+// writeFlatFiles 在每个 TS/JS 输出目录写入汇总文件 fileName.ts / fileName.js（扩展名按 file_ext），导出对象 objectName
+func writeFlatFiles(services []*ServiceInfo, config *PluginConfig, out *outputWriter, fileName, objectName string) error {
+	for _, outputPathConfig := range config.OutputPaths {
+		code := generateFlatTypeScript(services, serviceImportFor(outputPathConfig, config), objectName)
+		if err := out.write(outputPathConfig.Path, fileName+".ts", code); err != nil {
+			return err
+		}
+	}
+	for _, outputPathConfig := range config.OutputPathsJS {
+		code := generateFlatJavaScript(services, serviceImportForJS(outputPathConfig, config), objectName)
+		if err := out.write(outputPathConfig.Path, fileName+config.FileExt, code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestFlattenSinglePackage(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,flatten=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"))
+	code := generatedFile(t, generated, "ts/flatApi.ts")
+	assertContains(t, code,
+		"import type { CreateOrderReq, GetOrderReq, ListOrdersReq, ListOrdersResp, Order } from '@/api/proto-types/shop/v1/order';",
+		"export const api = {",
+		"orderGetOrder: (data: GetOrderReq): Promise<Order> =>",
+		"orderCreateOrder: (data: CreateOrderReq): Promise<Order> =>",
+	)
+}
+
+func TestFlattenRejectsDuplicateTypeNames(t *testing.T) {
+	_, err := runPlugin("output_paths=ts,flatten=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		orderFile("admin/v1/order.proto", "admin.v1", "AdminOrderService", "admin"))
+	assertErrorContains(t, err, "flatApi.ts 中的类型 Order 同时来自 @/api/proto-types/shop/v1/order 与 @/api/proto-types/admin/v1/order")
+}
+
+func TestFlattenJavaScriptIgnoresTypeNames(t *testing.T) {
+	// JS 汇总文件不导入类型，同名消息不影响生成
+	generated := mustRunPlugin(t, "output_paths_js=js,flatten=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		orderFile("admin/v1/order.proto", "admin.v1", "AdminOrderService", "admin"))
+	code := generatedFile(t, generated, "js/flatApi.js")
+	assertContains(t, code, "orderGetOrder: (data) =>", "adminOrderGetOrder: (data) =>")
+}
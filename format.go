@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// codeStyle quote / indent / semi / trailing_comma 指定的生成代码风格，零值表示保持默认输出
+// （单引号，TS 两个空格、JS 四个空格缩进，语句以 ; 结尾，多行对象/数组的末项是否带逗号与各生成位置一致）
+type codeStyle struct {
+	Quote         string // 字符串引号：single 或 double
+	Indent        string // 每层缩进（若干空格或 \t），为空时保持默认
+	NoSemi        bool   // semi=false：去掉行尾语句的 ;
+	TrailingComma string // 多行对象/数组末项的逗号：true 统一加上，false 统一去掉，为空时保持默认（由各生成位置直接写出，不经 formatCode）
+}
+
+// lastComma 返回多行对象/数组字面量末项后的逗号：trailing_comma=true 时为 ,，false 时为空，
+// 未设置时按生成位置的默认（def 为 true 时带逗号，如示例、hook 选项对象；API 对象的方法列表默认不带）
+func lastComma(trailingComma string, def bool) string {
+	if trailingComma == "true" || (trailingComma == "" && def) {
+		return ","
+	}
+	return ""
+}
+
+// joinMembers 以 ,\n 连接多行对象字面量的成员，末项按 trailing_comma 决定是否带逗号（默认不带）
+func joinMembers(members []string, trailingComma string) string {
+	if len(members) == 0 {
+		return ""
+	}
+	return strings.Join(members, ",\n") + lastComma(trailingComma, false)
+}
+
+// memberEnd 返回多行对象字面量中成员后的分隔：非末项为 ,\n，末项按 trailing_comma（默认带逗号）
+func memberEnd(last bool, trailingComma string) string {
+	if !last {
+		return ",\n"
+	}
+	return lastComma(trailingComma, true) + "\n"
+}
+
+// parseIndent 解析 indent：1-8 表示空格数，tab 表示制表符
+func parseIndent(value string) (string, error) {
+	if value == "tab" {
+		return "\t", nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 8 {
+		return "", fmt.Errorf("indent 只支持 1-8 或 tab: %s", value)
+	}
+	return strings.Repeat(" ", n), nil
+}
+
+// isDefault 判断是否为默认风格（不需要改写输出）
+func (s codeStyle) isDefault() bool {
+	return s.Quote != "double" && s.Indent == "" && !s.NoSemi
+}
+
+// formatCode 按 codeStyle 改写生成的 TS/JS 代码：
+// 单引号字符串改为双引号（注释、正则字面量与模板字符串的文本部分不变，${} 中的字符串同样改写）；
+// 指定 indent 时按括号嵌套重新缩进：行首缩进为最内层未闭合括号所在行的层级加一，以闭合括号开头的行与对应的开括号所在行对齐，
+// 上一行以 => 结尾（表达式体换行）时再加一层；多行注释的后续行与注释开头对齐；
+// semi=false 时去掉行尾的 ;（( 内除外），以 (、[、`、正则字面量开头的语句前补 ; 避免与上一行连在一起
+func formatCode(code string, style codeStyle) string {
+	if style.isDefault() {
+		return code
+	}
+	f := &formatter{style: style}
+	for _, line := range strings.Split(code, "\n") {
+		f.line(line)
+	}
+	return strings.Join(f.lines, "\n")
+}
+
+// openBracket 未闭合的括号
+type openBracket struct {
+	char  byte // ( [ {
+	level int  // 所在行的层级
+}
+
+// formatter formatCode 的扫描状态（跨行保留）
+type formatter struct {
+	style        codeStyle
+	lines        []string        // 已改写的行
+	cur          strings.Builder // 正在改写的行
+	brackets     []openBracket   // 未闭合的括号
+	templates    []int           // 嵌套的模板字符串：每项为所在 ${} 表达式中未闭合的 { 数量，-1 表示处于模板文本中
+	inComment    bool            // 处于多行注释中
+	commentLevel int             // 多行注释开头所在行的层级
+	level        int             // 当前行的层级
+	lastCode     string          // 上一个含代码的行去掉注释后的内容（semi=false 去掉的 ; 仍保留），用于判断 => 换行、语句是否结束及 / 是否开始正则
+	codeEnd      int             // 当前行中代码结束的位置
+	code         strings.Builder // 当前行的代码部分
+}
+
+// line 改写一行
+func (f *formatter) line(line string) {
+	inTemplateText := len(f.templates) > 0 && f.templates[len(f.templates)-1] < 0
+	trimmed := strings.TrimLeft(line, " \t")
+	f.cur.Reset()
+	f.code.Reset()
+	f.codeEnd = 0
+	switch {
+	case f.style.Indent == "" || inTemplateText:
+		// 不改缩进，或处于跨行的模板字符串文本中，原样保留行首
+		f.cur.WriteString(line[:len(line)-len(trimmed)])
+		if f.style.Indent == "" {
+			f.level = 0
+		}
+	case trimmed == "":
+	case f.inComment:
+		f.cur.WriteString(strings.Repeat(f.style.Indent, f.commentLevel))
+		if strings.HasPrefix(trimmed, "*") {
+			f.cur.WriteString(" ")
+		}
+	default:
+		f.level = 0
+		if n := len(f.brackets); n > 0 {
+			f.level = f.brackets[n-1].level + 1
+			if isCloser(trimmed[0]) {
+				f.level = f.brackets[n-1].level
+			}
+		}
+		if strings.HasSuffix(f.lastCode, "=>") && !isCloser(trimmed[0]) {
+			f.level++
+		}
+		f.cur.WriteString(strings.Repeat(f.style.Indent, f.level))
+	}
+	if f.style.NoSemi && !f.inComment && !inTemplateText && startsExpression(trimmed) && f.statementEnded() && (trimmed[0] != '/' || f.regexAllowed()) {
+		f.cur.WriteString(";")
+	}
+	f.scan(trimmed)
+	f.endLine()
+}
+
+// startsExpression 判断以 trimmed 开头的行在上一条语句没有 ; 时是否会与其连在一起：以 (、[、` 或正则字面量开头
+func startsExpression(trimmed string) bool {
+	if trimmed == "" {
+		return false
+	}
+	if trimmed[0] == '/' {
+		return !strings.HasPrefix(trimmed, "//") && !strings.HasPrefix(trimmed, "/*")
+	}
+	return strings.IndexByte("([`", trimmed[0]) >= 0
+}
+
+// isCloser 判断是否为闭合括号
+func isCloser(c byte) bool {
+	return c == ')' || c == ']' || c == '}'
+}
+
+// statementEnded 判断上一行代码是否已结束一条语句（semi=false 时下一行以 (、[、` 开头需要补 ;）
+func (f *formatter) statementEnded() bool {
+	if f.lastCode == "" || strings.HasSuffix(f.lastCode, "=>") {
+		return false
+	}
+	return strings.IndexByte("([{,=:?&|+-*/!<", f.lastCode[len(f.lastCode)-1]) < 0
+}
+
+// writeCode 写出代码部分的内容
+func (f *formatter) writeCode(s string) {
+	f.cur.WriteString(s)
+	f.code.WriteString(s)
+	if strings.TrimSpace(s) != "" {
+		f.codeEnd = f.cur.Len()
+	}
+}
+
+// scan 扫描一行（不含行首缩进）并写出，跟踪注释、字符串、模板字符串及括号
+func (f *formatter) scan(s string) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if f.inComment {
+			if strings.HasPrefix(s[i:], "*/") {
+				f.inComment = false
+				f.cur.WriteString("*/")
+				i++
+				continue
+			}
+			f.cur.WriteByte(c)
+			continue
+		}
+		if n := len(f.templates); n > 0 && f.templates[n-1] < 0 {
+			// 模板字符串文本
+			switch {
+			case c == '\\' && i+1 < len(s):
+				f.writeCode(s[i : i+2])
+				i++
+			case c == '`':
+				f.templates = f.templates[:n-1]
+				f.writeCode("`")
+			case strings.HasPrefix(s[i:], "${"):
+				f.templates[n-1] = 0
+				f.writeCode("${")
+				i++
+			default:
+				f.writeCode(string(c))
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(s[i:], "//"):
+			f.cur.WriteString(s[i:])
+			return
+		case strings.HasPrefix(s[i:], "/*"):
+			f.inComment = true
+			f.commentLevel = f.level
+			f.cur.WriteString("/*")
+			i++
+		case c == '\'' || c == '"':
+			end := stringEnd(s, i)
+			lit := s[i:end]
+			if c == '\'' && f.style.Quote == "double" {
+				lit = toDoubleQuoted(lit)
+			}
+			f.writeCode(lit)
+			i = end - 1
+		case c == '`':
+			f.templates = append(f.templates, -1)
+			f.writeCode("`")
+		case c == '/' && f.regexAllowed():
+			end := regexEnd(s, i)
+			f.writeCode(s[i:end])
+			i = end - 1
+		default:
+			if n := len(f.templates); n > 0 {
+				// ${} 表达式：} 闭合表达式时回到模板文本
+				if c == '{' {
+					f.templates[n-1]++
+				} else if c == '}' {
+					if f.templates[n-1] == 0 {
+						f.templates[n-1] = -1
+						f.writeCode("}")
+						continue
+					}
+					f.templates[n-1]--
+				}
+			} else if c == '(' || c == '[' || c == '{' {
+				f.brackets = append(f.brackets, openBracket{char: c, level: f.level})
+			} else if isCloser(c) && len(f.brackets) > 0 {
+				f.brackets = f.brackets[:len(f.brackets)-1]
+			}
+			f.writeCode(string(c))
+		}
+	}
+}
+
+// regexAllowed 判断当前位置的 / 是否开始一个正则字面量（而非除号）：前面没有代码，
+// 或前一个字符为运算符、( [ { , ; 等不能结束表达式的字符，或前面是 return / typeof 等关键字
+func (f *formatter) regexAllowed() bool {
+	before := strings.TrimSpace(f.code.String())
+	if before == "" {
+		before = f.lastCode
+	}
+	if before == "" {
+		return true
+	}
+	if strings.IndexByte("([{,;:=!&|?+-*%<>~^", before[len(before)-1]) >= 0 {
+		return true
+	}
+	for _, keyword := range []string{"return", "typeof", "case", "in", "of", "new", "delete", "void", "throw", "instanceof", "yield", "await"} {
+		if before == keyword || strings.HasSuffix(before, " "+keyword) || strings.HasSuffix(before, "\t"+keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// endLine 结束当前行：semi=false 时去掉行尾语句的 ;，记录行内代码部分
+func (f *formatter) endLine() {
+	line := f.cur.String()
+	code := strings.TrimSpace(f.code.String())
+	n := len(f.brackets)
+	if f.style.NoSemi && strings.HasSuffix(code, ";") && (n == 0 || f.brackets[n-1].char != '(') {
+		line = line[:f.codeEnd-1] + line[f.codeEnd:]
+		f.codeEnd--
+	}
+	if code != "" {
+		f.lastCode = code
+	}
+	f.lines = append(f.lines, line)
+}
+
+// stringEnd 返回 s[start] 处的引号字符串结束后的位置（处理转义），未闭合时为行尾
+func stringEnd(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// regexEnd 返回 s[start] 处的正则字面量结束后的位置（含 flags，处理转义及 [] 字符类中的 /），未闭合时为行尾
+func regexEnd(s string, start int) int {
+	inClass := false
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '[':
+			inClass = true
+		case s[i] == ']':
+			inClass = false
+		case s[i] == '/' && !inClass:
+			i++
+			for i < len(s) && (s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z') {
+				i++
+			}
+			return i
+		}
+	}
+	return len(s)
+}
+
+// toDoubleQuoted 将单引号字符串字面量改为双引号：去掉 \' 的转义，为 " 加上转义
+func toDoubleQuoted(lit string) string {
+	body := strings.TrimSuffix(strings.TrimPrefix(lit, "'"), "'")
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && i+1 < len(body):
+			if body[i+1] == '\'' {
+				b.WriteByte('\'')
+			} else {
+				b.WriteString(body[i : i+2])
+			}
+			i++
+		case body[i] == '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteByte(body[i])
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
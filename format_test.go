@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestTrailingCommaWrittenByGenerators(t *testing.T) {
+	param := "output_paths=ts,hooks=react-query,merge_defaults=true,body_key_case=snake"
+	// trailing_comma=none（同 false）：默认值、键名映射、API 对象及 hook 选项对象的末项都不带逗号
+	code := generatedFile(t, mustRunPlugin(t, param+",trailing_comma=none", itemFile()), "ts/itemApi.ts")
+	assertContains(t, code,
+		"const createItemDefaults = {\n  itemName: '',\n  unitPrice: 0,\n  sku: ''\n};",
+		"const createItemBodyKeys = {\n  itemName: 'item_name',\n  unitPrice: 'unit_price'\n};",
+		"service.get(`/v1/items/${encodeURIComponent(data.sku)}`, { ...getItemDefaults, ...data })\n};",
+		"mutationFn: (data: CreateItemReq) => itemApi.CreateItem(data)\n  });",
+		"queryFn: () => itemApi.GetItem(data)\n  });",
+	)
+	assertNotContains(t, code, ",\n}", ",\n  }")
+
+	// trailing_comma=true：末项都带逗号，包括默认不带逗号的 API 对象
+	code = generatedFile(t, mustRunPlugin(t, param+",trailing_comma=true", itemFile()), "ts/itemApi.ts")
+	assertContains(t, code,
+		"  sku: '',\n};",
+		"  unitPrice: 'unit_price',\n};",
+		"{ ...getItemDefaults, ...data }),\n};",
+		"itemApi.CreateItem(data),\n  });",
+		"itemApi.GetItem(data),\n  });",
+	)
+}
+
+func TestTrailingCommaInvalid(t *testing.T) {
+	_, err := runPlugin("output_paths=ts,trailing_comma=all", itemFile())
+	assertErrorContains(t, err, "trailing_comma 只支持 true、false、none: all")
+}
+
+// formatStyles formatCode 测试覆盖的代码风格
+var formatStyles = []codeStyle{
+	{Quote: "double"},
+	{Indent: "\t"},
+	{Indent: "   "},
+	{NoSemi: true},
+	{Quote: "double", Indent: "\t", NoSemi: true},
+}
+
+// nodeCheck 以 node --check 检查 code 作为 ES 模块能否通过语法检查，返回 node 的输出
+func nodeCheck(t *testing.T, node, code string) (string, bool) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "code.mjs")
+	if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(node, "--check", file).CombinedOutput()
+	return string(out), err == nil
+}
+
+// FuzzFormatCode 能通过 node --check 的代码按各风格改写后仍须通过
+func FuzzFormatCode(f *testing.F) {
+	for _, seed := range []string{
+		// 模板字符串：嵌套、跨行、${} 中的字符串与对象
+		"const a = `x ${b + `inner ${'q'}`} 'y'`;\nconst c = 'd';\n",
+		"const t = `line1\n  'quoted' ${{ k: 'v' }.k}\n}) line3`;\nconst u = [\n  1,\n];\n",
+		// 正则字面量：含引号、反引号、// 及字符类中的 /
+		"const r = /['\"`]/g;\nconst s = 'x'.replace(/\\/\\//, '');\n",
+		"const r = /[/'(]+/;\nconst q = ['a', /'{/];\nconst f = (v) => /^'\\d+'$/.test(v);\n",
+		"const x = 4 / 2 / 1;\nconst y = (x) / 2;\nconst z = [x][0] / 'a'.length;\n",
+		"const a = 1;\n/'x'/.test(a);\nconst b = a\n  / 2 / 'c'.length;\n",
+		// 注释中的引号与括号
+		"// it's a 'comment' (\nconst a = 'b'; // don't {\n/* it's\n   \"x\" ` [ */\nconst c = {\n  d: 'e',\n};\n",
+		// 转义与双引号
+		"const a = 'say \"hi\" it\\'s';\nconst b = \"it's\";\n",
+		// 语句与缩进
+		"const f = () => {\n  return 1;\n};\n(function () {})();\n[1, 2].forEach((x) => x);\n`a`.length;\n",
+		"const g = (a) =>\n  a\n    ? 1\n    : 2;\nfor (let i = 0; i < 1; i++) {\n  g(i);\n}\n",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, code string) {
+		node := requireNode(t)
+		if _, ok := nodeCheck(t, node, code); !ok {
+			t.Skip("输入本身不能通过 node --check")
+		}
+		for _, style := range formatStyles {
+			formatted := formatCode(code, style)
+			if out, ok := nodeCheck(t, node, formatted); !ok {
+				t.Errorf("%+v 改写后不能通过 node --check:\n%s\n%s", style, formatted, out)
+			}
+		}
+	})
+}
+
+func TestFormattedOutputPassesNodeCheck(t *testing.T) {
+	node := requireNode(t)
+	options := []string{
+		"",
+		"arrow_style=block,hooks=react-query,emit_infinite_queries=true",
+		"hooks=swr,merge_defaults=true,body_key_case=snake",
+		"framework=vue,client=fetch,error_tuple=true",
+		"emit_zod=true,emit_examples=true,emit_paths=true,emit_path_builders=true,emit_enum_labels=true",
+		"protocol=connect,cache_get=30,retry=2",
+	}
+	styles := []string{
+		"quote=double",
+		"indent=tab",
+		"semi=false",
+		"trailing_comma=false",
+		"quote=double,indent=3,semi=false,trailing_comma=true",
+	}
+	files := []*descriptorpb.FileDescriptorProto{
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		statusFile(),
+		itemFile(),
+	}
+	for _, option := range options {
+		t.Run(option, func(t *testing.T) {
+			t.Parallel()
+			for _, style := range styles {
+				param := "output_paths_js=js," + style
+				if option != "" {
+					param += "," + option
+				}
+				for name, code := range mustRunPlugin(t, param, files...) {
+					if !strings.HasSuffix(name, ".js") {
+						continue
+					}
+					if out, ok := nodeCheck(t, node, code); !ok {
+						t.Errorf("%s: %s 不能通过 node --check:\n%s", param, name, out)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// malformedRuleFile 返回 HTTP 规则不合法的服务：GetOrder 路径为空，Ping 的 custom kind 含空格，ListOrders 花括号不成对
+func malformedRuleFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("shop/v1/order.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("GetOrderReq", protoField("order_id", 1, typeString, "")),
+		},
+		protoService("OrderService",
+			protoMethod("GetOrder", ".shop.v1.GetOrderReq", ".google.protobuf.Empty", httpGet("")),
+			protoMethod("Ping", ".google.protobuf.Empty", ".google.protobuf.Empty", httpCustom("HE AD", "/v1/ping")),
+			protoMethod("ListOrders", ".shop.v1.GetOrderReq", ".google.protobuf.Empty", httpGet("/v1/orders/{order_id")),
+			protoMethod("DeleteOrder", ".shop.v1.GetOrderReq", ".google.protobuf.Empty", httpDelete("/v1/orders/{order_id}")),
+		),
+	)
+}
+
+func TestMalformedHttpRuleIsReported(t *testing.T) {
+	_, err := runPlugin("output_paths=ts,check_only=true", malformedRuleFile())
+	assertErrorContains(t, err, "shop.v1.OrderService.GetOrder: 无法识别的 HTTP 规则")
+	assertErrorContains(t, err, "shop.v1.OrderService.Ping: 无法识别的 HTTP 规则")
+	assertErrorContains(t, err, "shop.v1.OrderService.ListOrders: 路径 \"/v1/orders/{order_id\" 不合法")
+}
+
+func TestMalformedHttpRuleIsSkipped(t *testing.T) {
+	// 正常生成时只给出警告：无法识别的方法不生成，其余方法照常生成
+	generated := mustRunPlugin(t, "output_paths=ts", malformedRuleFile())
+	code := generatedFile(t, generated, "ts/orderApi.ts")
+	assertContains(t, code, "DeleteOrder: (data: GetOrderReq): Promise<Empty> =>")
+	assertNotContains(t, code, "GetOrder:", "Ping:")
+}
+
+func TestHttpPattern(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        *annotations.HttpRule
+		defaultVerb string
+		want        *HttpRule // nil 表示无法识别
+	}{
+		{"get", httpGet("/v1/orders"), "", &HttpRule{Method: "get", Path: "/v1/orders"}},
+		{"post", httpPost("/v1/orders", "*"), "", &HttpRule{Method: "post", Path: "/v1/orders"}},
+		{"put", &annotations.HttpRule{Pattern: &annotations.HttpRule_Put{Put: "/v1/orders/{id}"}}, "", &HttpRule{Method: "put", Path: "/v1/orders/{id}"}},
+		{"delete", httpDelete("/v1/orders/{id}"), "", &HttpRule{Method: "delete", Path: "/v1/orders/{id}"}},
+		{"patch", &annotations.HttpRule{Pattern: &annotations.HttpRule_Patch{Patch: "/v1/orders/{id}"}}, "", &HttpRule{Method: "patch", Path: "/v1/orders/{id}"}},
+		{"custom", httpCustom("HEAD", "/v1/ping"), "", &HttpRule{Method: "head", Path: "/v1/ping"}},
+		{"custom options", httpCustom("OPTIONS", "/v1/orders"), "", &HttpRule{Method: "options", Path: "/v1/orders"}},
+		{"custom 无效 kind 使用 default_verb", httpCustom("HE AD", "/v1/ping"), "post", &HttpRule{Method: "post", Path: "/v1/ping", Fallback: `custom "HE AD"`}},
+		{"custom 无效 kind", httpCustom("HE AD", "/v1/ping"), "", nil},
+		{"custom 空路径", httpCustom("HEAD", ""), "post", nil},
+		{"空路径", httpGet(""), "", nil},
+		{"未设置 pattern", &annotations.HttpRule{}, "", nil},
+		{"nil", nil, "", nil},
+	}
+	for _, tt := range tests {
+		got := httpPattern(tt.rule, tt.defaultVerb)
+		switch {
+		case tt.want == nil && got != nil:
+			t.Errorf("%s: httpPattern = %+v, want nil", tt.name, *got)
+		case tt.want != nil && got == nil:
+			t.Errorf("%s: httpPattern = nil, want %+v", tt.name, *tt.want)
+		case tt.want != nil && *got != *tt.want:
+			t.Errorf("%s: httpPattern = %+v, want %+v", tt.name, *got, *tt.want)
+		}
+	}
+}
+
+// customVerbFile 返回以 custom 规则声明 HEAD、OPTIONS 方法的服务
+func customVerbFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("shop/v1/order.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("GetOrderReq", protoField("order_id", 1, typeString, "")),
+		},
+		protoService("OrderService",
+			protoMethod("CheckOrder", ".shop.v1.GetOrderReq", ".google.protobuf.Empty", httpCustom("HEAD", "/v1/orders/{order_id}")),
+			protoMethod("OrderOptions", ".shop.v1.GetOrderReq", ".google.protobuf.Empty", httpCustom("OPTIONS", "/v1/orders")),
+		),
+	)
+}
+
+func TestCustomVerbsGenerated(t *testing.T) {
+	// custom 规则的 kind 小写后作为 service 上的方法名，与其余方法一样生成
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js", customVerbFile())
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"  CheckOrder: (data: GetOrderReq): Promise<Empty> =>\n    service.head(`/v1/orders/${encodeURIComponent(data.orderId)}`, data),\n",
+		"  OrderOptions: (data: GetOrderReq): Promise<Empty> =>\n    service.options('/v1/orders', data)\n",
+	)
+	assertContains(t, generatedFile(t, generated, "js/orderApi.js"),
+		"CheckOrder: (data) => service.head(`/v1/orders/${encodeURIComponent(data.orderId)}`, data),",
+		"OrderOptions: (data) => service.options('/v1/orders', data)",
+	)
+}
+
+func TestHttpRuleBody(t *testing.T) {
+	if got := httpRuleFromPattern(httpPost("/v1/orders", "order"), ""); got == nil || got.Body != "order" {
+		t.Errorf("httpRuleFromPattern 应保留 body: %+v", got)
+	}
+}
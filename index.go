@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// sortedByFileName 返回按 API 文件路径排序的服务副本，保证汇总文件稳定
+func sortedByFileName(services []*ServiceInfo) []*ServiceInfo {
+	sorted := append([]*ServiceInfo(nil), services...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return serviceFilePath(*sorted[i]) < serviceFilePath(*sorted[j])
+	})
+	return sorted
+}
+
+// generatedTypeNames 返回服务 TS 文件中自行生成并导出的类型名（XxxQuery、XxxResult），Page、StrictXxx 由汇总文件单独去重导出
+func generatedTypeNames(svc ServiceInfo) []string {
+	var names []string
+	if svc.SplitQueryTypes {
+		for _, method := range svc.Methods {
+			if hasQueryType(svc, method) {
+				names = append(names, method.MethodName+"Query")
+			}
+		}
+	}
+	if svc.EmitResultUnion {
+		for _, method := range svc.Methods {
+			if t := responseType(svc, method); t != "void" {
+				names = append(names, svc.ServiceName+"Result")
+				break
+			}
+		}
+	}
+	if svc.EmitOpsMap {
+		names = append(names, opsTypeName(svc))
+	}
+	return names
+}
+
+// generateIndex 生成输出目录的汇总入口 index.ts / index.js：重新导出每个服务的 API 对象
+// typed 为 true 时（TS）同时以 export type 重新导出请求/响应类型（来自 ts-proto）及各服务文件中生成的类型
+func generateIndex(services []*ServiceInfo, typed bool) []byte {
+	var buf bytes.Buffer
+	writeHeader(&buf, aggregateHeader(*services[0]))
+	sorted := sortedByFileName(services)
+	// package_dirs 时不同包下的同名服务对象名相同，只导出第一个
+	owners := make(map[string]string)
+	for _, svc := range sorted {
+		if owner, ok := owners[svc.ApiFileName]; ok {
+			logf("警告: %s 与 %s 导出的对象名相同: %s，汇总文件只导出前者", owner, serviceFilePath(*svc), svc.ApiFileName)
+			continue
+		}
+		owners[svc.ApiFileName] = serviceFilePath(*svc)
+		buf.WriteString("export { ")
+		buf.WriteString(svc.ApiFileName)
+		buf.WriteString(" } from './")
+		buf.WriteString(serviceFilePath(*svc))
+		buf.WriteString("';\n")
+	}
+	if !typed {
+		return buf.Bytes()
+	}
+
+	buf.WriteString("\n")
+	writeTypeStatements(&buf, "export", services[0].TypesImportPath, mergeTypeImports(services))
+
+	// Page 与 StrictXxx 可能在多个服务文件中都有定义，只从第一个文件导出一次（来源不同的同名类型已由 indexTypeConflicts 报错）
+	pageExported := false
+	strictExported := make(map[string]bool)
+	for _, svc := range sorted {
+		names := generatedTypeNames(*svc)
+		if _, _, ok := pageKeys(*svc); ok && !pageExported {
+			names = append([]string{"Page"}, names...)
+			pageExported = true
+		}
+		for _, name := range strictNamesOf(svc.StrictTypes) {
+			if !strictExported[name] {
+				strictExported[name] = true
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		buf.WriteString("export type { ")
+		for i, name := range uniqueAndSort(names) {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(name)
+		}
+		buf.WriteString(" } from './")
+		buf.WriteString(serviceFilePath(*svc))
+		buf.WriteString("';\n")
+	}
+	return buf.Bytes()
+}
+
+// hookNames 返回服务文件中导出的 hook 名（React Query / SWR hook 或 Vue 组合式函数）
+func hookNames(svc ServiceInfo) []string {
+	var names []string
+	if hasInfiniteQueries(svc) {
+		for _, method := range svc.Methods {
+			if method.Paginated {
+				names = append(names, "useInfinite"+method.MethodName)
+			}
+		}
+	}
+	if svc.Hooks != "" || svc.Framework == "vue" {
+		for _, method := range svc.Methods {
+			names = append(names, queryHookName(method))
+		}
+	}
+	return names
+}
+
+// generateHooksIndex 生成 hooks.ts / hooks.js：重新导出所有服务文件中生成的 hook；没有 hook 时返回 nil
+// 不同服务的 hook 重名时只导出第一个并给出警告
+func generateHooksIndex(services []*ServiceInfo) []byte {
+	var buf bytes.Buffer
+	owners := make(map[string]string)
+	wrote := false
+	for _, svc := range sortedByFileName(services) {
+		var names []string
+		for _, name := range hookNames(*svc) {
+			if owner, ok := owners[name]; ok {
+				logf("警告: %s 与 %s 生成的 hook 重名: %s，hooks 汇总文件只导出前者", owner, svc.ApiFileName, name)
+				continue
+			}
+			owners[name] = svc.ApiFileName
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		if !wrote {
+			writeHeader(&buf, aggregateHeader(*svc))
+			wrote = true
+		}
+		buf.WriteString("export { ")
+		buf.WriteString(strings.Join(names, ", "))
+		buf.WriteString(" } from './")
+		buf.WriteString(serviceFilePath(*svc))
+		buf.WriteString("';\n")
+	}
+	if !wrote {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// warnIndexShadowsServiceImport 汇总文件与 service_import 指向同一模块（如 index_name=api 且 service_import=./api）时给出警告，
+// 否则生成的 API 文件会导入汇总文件而不是请求封装
+func warnIndexShadowsServiceImport(indexName, serviceImport string) {
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(serviceImport, ".ts"), ".js")
+	if trimmed == "./"+indexName || (indexName == "index" && trimmed == ".") {
+		logf("警告: 汇总文件 %s 与 service_import %s 指向同一模块，请修改 index_name 或 service_import", indexName, serviceImport)
+	}
+}
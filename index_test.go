@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// userFile 返回包 pkg 下的 UserService：getMethod 以 GET /v1/users/{user_id} 返回 User，ListUsers 返回分页响应
+func userFile(path, pkg, getMethod string) *descriptorpb.FileDescriptorProto {
+	user := "." + pkg + ".User"
+	return protoFile(path, pkg,
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("User", protoField("user_id", 1, typeString, "")),
+			protoMessage("GetUserReq", protoField("user_id", 1, typeString, "")),
+			protoMessage("ListUsersReq", protoField("page_token", 1, typeString, "")),
+			protoMessage("ListUsersResp",
+				repeatedField("items", 1, typeMessage, user),
+				protoField("next_page_token", 2, typeString, ""),
+			),
+		},
+		protoService("UserService",
+			protoMethod(getMethod, "."+pkg+".GetUserReq", user, httpGet("/v1/users/{user_id}")),
+			protoMethod("ListUsers", "."+pkg+".ListUsersReq", "."+pkg+".ListUsersResp", httpGet("/v1/users")),
+		),
+	)
+}
+
+func TestIndexReexportsTypesFromTwoPackages(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js,generate_index=true,typed_pages=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		userFile("admin/v1/user.proto", "admin.v1", "GetUser"))
+	code := generatedFile(t, generated, "ts/index.ts")
+	assertContains(t, code,
+		"export { orderApi } from './orderApi';\nexport { userApi } from './userApi';",
+		"export type { GetUserReq, ListUsersReq, User } from '@/api/proto-types/admin/v1/user';",
+		"export type { CreateOrderReq, GetOrderReq, ListOrdersReq, Order } from '@/api/proto-types/shop/v1/order';",
+		// 两个服务的 Page<T> 键名相同，只导出一次
+		"export type { Page } from './orderApi';",
+	)
+	if n := strings.Count(code, "Page"); n != 1 {
+		t.Errorf("Page 应只导出一次，实际出现 %d 次:\n%s", n, code)
+	}
+	js := generatedFile(t, generated, "js/index.js")
+	assertContains(t, js, "export { orderApi } from './orderApi';")
+	assertNotContains(t, js, "export type")
+}
+
+func TestIndexRejectsDuplicateTypeNames(t *testing.T) {
+	_, err := runPlugin("output_paths=ts,generate_index=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		orderFile("admin/v1/order.proto", "admin.v1", "AdminOrderService", "admin"))
+	assertErrorContains(t, err, "index.ts 中的类型 Order 同时来自 @/api/proto-types/admin/v1/order 与 @/api/proto-types/shop/v1/order")
+}
+
+func TestIndexRejectsDuplicateQueryTypes(t *testing.T) {
+	// 两个服务都有 GET 方法 GetOrder，split_query_types 时各自生成 GetOrderQuery
+	_, err := runPlugin("output_paths=ts,generate_index=true,split_query_types=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		userFile("admin/v1/user.proto", "admin.v1", "GetOrder"))
+	assertErrorContains(t, err, "index.ts 中的类型 GetOrderQuery 同时来自 ./orderApi 与 ./userApi")
+}
+
+func TestIndexJavaScriptIgnoresTypeNames(t *testing.T) {
+	// index.js 只导出 API 对象，同名消息不影响生成
+	generated := mustRunPlugin(t, "output_paths_js=js,generate_index=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		orderFile("admin/v1/order.proto", "admin.v1", "AdminOrderService", "admin"))
+	assertContains(t, generatedFile(t, generated, "js/index.js"),
+		"export { adminOrderApi } from './adminOrderApi';\nexport { orderApi } from './orderApi';")
+}
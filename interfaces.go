@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// interfacesFileName emit_interfaces 时生成的类型文件名（不含扩展名），服务文件从 ./types 导入类型
+const interfacesFileName = "types"
+
+// messageTypeName 返回消息在 types.ts 中的类型名，与 ts-proto 一致：嵌套消息以 _ 连接（例如：Order.Item -> Order_Item）
+func messageTypeName(msg *protogen.Message) string {
+	fullName := string(msg.Desc.FullName())
+	if pkg := string(msg.Desc.ParentFile().Package()); pkg != "" {
+		fullName = strings.TrimPrefix(fullName, pkg+".")
+	}
+	return strings.ReplaceAll(fullName, ".", "_")
+}
+
+// interfaceRoots 返回方法请求/响应中需要在 types.ts 中声明的消息：请求、响应及 typed_pages 的列表元素
+// Struct / Value / ListValue 直接使用 JSON 类型，verb_response 为 void 的响应不使用，均不计入
+func interfaceRoots(methods []MethodInfo, verbResponses map[string]string) []*protogen.Message {
+	var roots []*protogen.Message
+	for _, m := range methods {
+		if !isJSONWellKnown(m.Input) {
+			roots = append(roots, m.Input)
+		}
+		if verbResponses[m.HttpMethod] == "void" {
+			continue
+		}
+		if m.PageItem != nil {
+			roots = append(roots, m.PageItem)
+		} else if !isJSONWellKnown(m.Output) {
+			roots = append(roots, m.Output)
+		}
+	}
+	return roots
+}
+
+// localTypeImports emit_interfaces 时将按 ts-proto 文件分组的类型导入合并为从 types.ts 导入
+func localTypeImports(typeImports map[string][]string) map[string][]string {
+	var names []string
+	for _, typeNames := range typeImports {
+		names = append(names, typeNames...)
+	}
+	if len(names) == 0 {
+		return map[string][]string{}
+	}
+	return map[string][]string{"": uniqueAndSort(names)}
+}
+
+// wellKnownTSType 返回 google.protobuf 下消息的 TS 类型，按 HTTP 上传输的 JSON 表示映射：
+// Timestamp 默认为 ISO 字符串（timestamp_type=Date 时为 Date），Duration 为 "1.5s" 形式的字符串，其余与 ts-proto 一致
+func wellKnownTSType(msg *protogen.Message, timestampType string) string {
+	switch msg.Desc.Name() {
+	case "Timestamp":
+		return timestampType
+	case "Duration":
+		return "string"
+	case "Struct":
+		return "{ [key: string]: any }"
+	case "ListValue":
+		return "any[]"
+	case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value", "UInt32Value":
+		return "number | undefined"
+	case "BoolValue":
+		return "boolean | undefined"
+	case "StringValue":
+		return "string | undefined"
+	case "BytesValue":
+		return "Uint8Array | undefined"
+	case "FieldMask":
+		return "string[]"
+	case "Empty":
+		return "{}"
+	}
+	return "any"
+}
+
+// interfaceSet types.ts 中声明的消息与枚举（类型名 -> 定义），用于去重及发现不同 proto 类型生成相同类型名的冲突
+type interfaceSet struct {
+	messages map[string]*protogen.Message
+	enums    map[string]*protogen.Enum
+	owners   map[string]string // 类型名 -> proto 全名
+}
+
+// add 记录类型名对应的 proto 全名，已被其他 proto 类型占用时返回错误
+func (s *interfaceSet) add(name, fullName string) (bool, error) {
+	if owner, ok := s.owners[name]; ok {
+		if owner != fullName {
+			return false, fmt.Errorf("emit_interfaces: %s 与 %s 生成的类型名相同: %s", owner, fullName, name)
+		}
+		return false, nil
+	}
+	s.owners[name] = fullName
+	return true, nil
+}
+
+// walk 递归收集消息及其字段引用的消息、枚举；google.protobuf 下的消息按 wellKnownTSType 映射，不展开
+func (s *interfaceSet) walk(msg *protogen.Message) error {
+	if msg.Desc.ParentFile().Package() == "google.protobuf" {
+		return nil
+	}
+	added, err := s.add(messageTypeName(msg), string(msg.Desc.FullName()))
+	if err != nil || !added {
+		return err
+	}
+	s.messages[messageTypeName(msg)] = msg
+	for _, field := range msg.Fields {
+		if field.Desc.IsMap() {
+			field = field.Message.Fields[1]
+		}
+		if field.Enum != nil {
+			if _, err := s.add(enumTypeName(field.Enum), string(field.Enum.Desc.FullName())); err != nil {
+				return err
+			}
+			s.enums[enumTypeName(field.Enum)] = field.Enum
+		}
+		if field.Message != nil {
+			if err := s.walk(field.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generateInterfaces 生成 emit_interfaces 的 types.ts：为所有服务方法的请求/响应消息及其引用的消息、枚举声明 TS 类型，
+// 服务文件导入的类型名（消息名）与 ts-proto 命名不同时（嵌套消息）另外声明别名；不同 proto 类型生成相同类型名时返回错误
+func generateInterfaces(services []*ServiceInfo) ([]byte, error) {
+	set := &interfaceSet{
+		messages: make(map[string]*protogen.Message),
+		enums:    make(map[string]*protogen.Enum),
+		owners:   make(map[string]string),
+	}
+	aliases := make(map[string]string) // 服务文件中使用的类型名 -> types.ts 中的类型
+	for _, svc := range services {
+		for _, root := range svc.InterfaceRoots {
+			if err := set.walk(root); err != nil {
+				return nil, err
+			}
+			name := string(root.Desc.Name())
+			target := messageTypeName(root)
+			if root.Desc.ParentFile().Package() == "google.protobuf" {
+				target = wellKnownTSType(root, services[0].TimestampType)
+			}
+			if name == target {
+				continue
+			}
+			if _, err := set.add(name, string(root.Desc.FullName())); err != nil {
+				return nil, err
+			}
+			aliases[name] = target
+		}
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, aggregateHeader(*services[0]))
+
+	names := make([]string, 0, len(set.owners))
+	for name := range set.owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch {
+		case set.enums[name] != nil:
+			writeEnumDeclaration(&buf, name, set.enums[name])
+		case set.messages[name] != nil:
+			writeInterface(&buf, name, set.messages[name], services[0].UseJSONNames, services[0].TimestampType)
+		default:
+			buf.WriteString("export type " + name + " = " + aliases[name] + ";\n\n")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeEnumDeclaration 写入枚举声明（值与 proto 一致）
+func writeEnumDeclaration(buf *bytes.Buffer, name string, e *protogen.Enum) {
+	buf.WriteString(jsDocComment(strings.TrimSpace(string(e.Comments.Leading)), nil, ""))
+	buf.WriteString("export enum " + name + " {\n")
+	for _, v := range e.Values {
+		buf.WriteString("  " + string(v.Desc.Name()) + " = " + strconv.Itoa(int(v.Desc.Number())) + ",\n")
+	}
+	buf.WriteString("}\n\n")
+}
+
+// writeInterface 写入消息的 interface 声明，字段键与 ts-proto 一致，字段注释写为 JSDoc
+func writeInterface(buf *bytes.Buffer, name string, msg *protogen.Message, useJSON bool, timestampType string) {
+	buf.WriteString(jsDocComment(strings.TrimSpace(string(msg.Comments.Leading)), nil, ""))
+	buf.WriteString("export interface " + name + " {\n")
+	for _, field := range msg.Fields {
+		tags := []string(nil)
+		if isDeprecatedField(field) {
+			tags = append(tags, "@deprecated")
+		}
+		buf.WriteString(jsDocComment(strings.TrimSpace(string(field.Comments.Leading)), tags, "  "))
+		buf.WriteString("  ")
+		buf.WriteString(exampleKey(fieldKey(field, useJSON)))
+		// 与 ts-proto 一致：消息字段、proto3 optional 及 oneof 成员可为 undefined
+		if !field.Desc.IsList() && !field.Desc.IsMap() && (field.Message != nil || field.Desc.HasOptionalKeyword() || field.Oneof != nil) {
+			buf.WriteString("?")
+		}
+		buf.WriteString(": ")
+		buf.WriteString(fieldTSType(field, timestampType))
+		buf.WriteString(";\n")
+	}
+	buf.WriteString("}\n\n")
+}
+
+// fieldTSType 返回字段的 TS 类型：repeated 为数组，map 为索引签名
+func fieldTSType(field *protogen.Field, timestampType string) string {
+	switch {
+	case field.Desc.IsMap():
+		key := "string"
+		if k := field.Message.Fields[0].Desc.Kind(); k != protoreflect.StringKind && k != protoreflect.BoolKind {
+			key = "number"
+		}
+		return "{ [key: " + key + "]: " + singularTSType(field.Message.Fields[1], timestampType) + " }"
+	case field.Desc.IsList():
+		t := singularTSType(field, timestampType)
+		if strings.Contains(t, " ") {
+			t = "(" + t + ")"
+		}
+		return t + "[]"
+	}
+	return singularTSType(field, timestampType)
+}
+
+// singularTSType 返回单个值的 TS 类型（64 位整数为 number，bytes 为 Uint8Array，well-known types 见 wellKnownTSType）
+func singularTSType(field *protogen.Field, timestampType string) string {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return "Uint8Array"
+	case protoreflect.EnumKind:
+		return enumTypeName(field.Enum)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if field.Message.Desc.ParentFile().Package() == "google.protobuf" {
+			return wellKnownTSType(field.Message, timestampType)
+		}
+		return messageTypeName(field.Message)
+	}
+	return "number"
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// withJSONName 将字段的 json_name 改为 name（如 [json_name = "oid"]）
+func withJSONName(field *descriptorpb.FieldDescriptorProto, name string) *descriptorpb.FieldDescriptorProto {
+	field.JsonName = proto.String(name)
+	return field
+}
+
+// jsonNameFile 返回显式设置了 json_name 的订单服务：路径变量、body 字段及其余查询字段的 json_name 都与 camelCase 不同
+func jsonNameFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("shop/v1/order.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("Order", withJSONName(protoField("order_id", 1, typeString, ""), "oid")),
+			protoMessage("GetOrderReq", withJSONName(protoField("order_id", 1, typeString, ""), "oid")),
+			protoMessage("UpdateOrderReq",
+				withJSONName(protoField("order_id", 1, typeString, ""), "oid"),
+				withJSONName(protoField("order", 2, typeMessage, ".shop.v1.Order"), "payload"),
+				withJSONName(protoField("update_mask", 3, typeString, ""), "mask"),
+			),
+		},
+		protoService("OrderService",
+			protoMethod("GetOrder", ".shop.v1.GetOrderReq", ".shop.v1.Order", httpGet("/v1/orders/{order_id}")),
+			protoMethod("UpdateOrder", ".shop.v1.UpdateOrderReq", ".shop.v1.Order", httpPost("/v1/orders/{order_id}", "order")),
+		),
+	)
+}
+
+func TestUseJSONNames(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,emit_interfaces=true,use_json_names=true", jsonNameFile())
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"service.get(`/v1/orders/${encodeURIComponent(data.oid)}`, data)",
+		"service.post(`/v1/orders/${encodeURIComponent(data.oid)}`, data.payload, { params: { mask: data.mask } })",
+	)
+	assertContains(t, generatedFile(t, generated, "ts/types.ts"), "  oid: string;", "  payload?: Order;", "  mask: string;")
+	assertNotContains(t, generatedFile(t, generated, "ts/types.ts"), "orderId", "updateMask")
+}
+
+func TestProtoNamesByDefault(t *testing.T) {
+	// 默认按 ts-proto 的 camelCase 键名，忽略 json_name
+	generated := mustRunPlugin(t, "output_paths=ts,emit_interfaces=true", jsonNameFile())
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"service.get(`/v1/orders/${encodeURIComponent(data.orderId)}`, data)",
+		"service.post(`/v1/orders/${encodeURIComponent(data.orderId)}`, data.order, { params: { updateMask: data.updateMask } })",
+	)
+	assertContains(t, generatedFile(t, generated, "ts/types.ts"), "  orderId: string;", "  order?: Order;", "  updateMask: string;")
+	assertNotContains(t, generatedFile(t, generated, "ts/types.ts"), "oid", "payload", "mask:")
+}
@@ -2,17 +2,21 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
-	"reflect"
+	"runtime/debug"
 	"sort"
+	"strconv"
 	"strings"
 
 	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/genproto/googleapis/api/visibility"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
 )
 
 // 输出路径配置
@@ -23,99 +27,557 @@ type OutputPathConfig struct {
 
 // 插件配置
 type PluginConfig struct {
-	ServiceImport   string             // service 导入路径（TS，及 JS 在未指定 service_import_js 时）
-	ServiceImportJS string             // JS 专用 service 导入路径（可选，如 '@/api/api.js'）
-	TypesImportPath string             // 类型定义导入路径前缀（如 '@/api/proto-types'，仅 TS 使用）
-	OutputPaths     []OutputPathConfig // TS 输出路径
-	OutputPathsJS   []OutputPathConfig // JS 输出路径（按 addressApi.js 风格，无类型 import）
+	ServiceImport            string             // service 导入路径（TS，及 JS 在未指定 service_import_js 时）
+	ServiceImportJS          string             // JS 专用 service 导入路径（可选，如 '@/api/api.js'）
+	TypesImportPath          string             // 类型定义导入路径前缀（如 '@/api/proto-types'，仅 TS 使用）
+	OutputPaths              []OutputPathConfig // TS 输出路径
+	OutputPathsJS            []OutputPathConfig // JS 输出路径（按 addressApi.js 风格，无类型 import）
+	SplitQueryTypes          bool               // GET 方法是否单独生成 XxxQuery 类型（仅 TS）
+	VersionInHeader          bool               // 是否在生成文件头部注释插件版本
+	MethodClients            map[string]string  // 按方法覆盖 service 上调用的方法名（Method 或 Service.Method -> 方法名）
+	EmitEnumHelpers          bool               // 是否为请求/响应中用到的枚举生成数值与名称互转函数
+	DefaultVerb              string             // 无法识别的 HTTP 规则（如 kind 不是字母的 custom）回退使用的 HTTP 方法，为空时跳过该方法
+	EmitPathBuilders         bool               // 是否为每个方法生成只返回 URL 的 XxxPath 函数
+	BundleDts                bool               // 是否为 JS 输出目录生成汇总声明文件 api.d.ts
+	VerbResponses            map[string]string  // 按 HTTP 方法指定响应处理：void（丢弃响应体）、data（取 res.data）、raw（原样返回）
+	FirstAcronym             string             // 服务名开头缩写词的小写策略：lower（整体小写）、first（只小写首字母）、preserve（保留）
+	EmitInfiniteQueries      bool               // 是否为分页方法生成 React Query 的 useInfiniteXxx hook
+	OutputZip                string             // 将所有生成文件打包写入该 zip 文件，而不是直接写入输出目录
+	UseJSONNames             bool               // 生成代码中的字段键名是否使用 proto 的 json_name（默认使用 proto 字段名的 camelCase）
+	CheckOnly                bool               // 只校验注解（HTTP 规则、路径模板、文件名冲突），不写入任何文件
+	EmitRequestTypeNames     bool               // 是否生成方法名到请求消息名的映射常量 XxxRequestTypes
+	ArrowStyle               string             // 方法箭头函数体风格：concise（表达式体）、block（{ return ...; } 块体）
+	InlineRequestEnums       bool               // 是否为请求中用到的枚举生成值常量
+	VerifyServiceImport      bool               // 是否校验相对路径形式的 service 导入在各输出目录下能否解析到文件
+	EmitRegistryAugmentation bool               // 是否在 TS 文件中为全局 ApiRegistry 接口追加该服务（declare global）
+	EmitExamples             bool               // 是否根据字段注释中的 @example 生成请求/响应示例常量
+	Flatten                  bool               // 是否将所有服务的方法汇总到一个扁平对象（flatApi.ts/js），不再按服务生成文件
+	EncodePathParams         bool               // 单段路径变量是否包裹 encodeURIComponent，默认 true
+	EmitResultUnion          bool               // 是否在 TS 文件中生成服务所有响应类型的联合类型（XxxResult）
+	ErrorTuple               bool               // 是否生成返回 [err, data] 元组、不抛出异常的方法（async + try/catch）
+	OutputDir                string             // 旧版单目录参数 output_dir，按 lang 在未配置对应 output_paths / output_paths_js 时作为唯一的输出目录
+	TypedPages               bool               // 是否将标准分页响应（items + next_page_token）的返回类型生成为 Page<T>
+	GenerateIndex            bool               // 是否在每个输出目录生成汇总入口 index.ts / index.js
+	DeepComments             bool               // protogen 的服务注释为空时是否直接从 SourceCodeInfo 提取（前置注释或分离注释）
+	IndexName                string             // generate_index 汇总文件名（不含扩展名），默认 index
+	EmitPackageJSON          bool               // 是否在每个输出目录生成 package.json（type: module 与 exports），便于作为子包引用
+	IncludeInternal          bool               // 是否生成 google.api.method_visibility 标记为 INTERNAL 的方法（默认跳过）
+	EmitZod                  bool               // 是否为每个方法的请求消息生成 zod schema
+	DumpConfig               string             // 非空时在生成前将解析后的完整配置以 JSON 写入该文件，便于排查参数解析
+	MergeDefaults            bool               // 是否将调用方数据合并到请求默认值上再发送（{ ...defaults, ...data }）
+	Retry                    int                // 失败后的最大重试次数，0 表示不重试
+	RetryStatuses            []int              // 只在这些 HTTP 状态码时重试，为空时所有失败都重试
+	EmitConfigure            bool               // 是否生成模块级配置与 configure(cfg) 函数（baseURL、headers）
+	StrictNull               bool               // 是否为响应生成 StrictXxx 类型，将消息字段、optional 字段标记为可能为 undefined
+	CallStyle                string             // service 调用风格：args（service.post(path, data)）、fluent（service.url(path).post(data)）
+	BodyKeyCase              string             // 请求体顶层键名的大小写风格：snake、camel、pascal、kebab，为空时不改写
+	EmitAbortAll             bool               // 是否为每个模块生成 abortAll()，取消该模块所有进行中的请求
+	Lang                     string             // output_dir 生成的语言：js（默认）或 ts
+	MergeByPackage           bool               // 是否按 proto package 将服务合并为一个文件（如 shopV1Api.ts），不再按服务生成文件
+	Client                   string             // 请求方式：service（默认，调用导入的 service）或 fetch（生成基于 fetch 的 request 辅助函数，无需 service）
+	CompatArgs               bool               // 生成的方法是否同时接受单个请求对象或按字段顺序的位置参数
+	GetParams                string             // GET 请求数据的传法：data（默认，作为第二个参数）或 query（作为 { params: data }）
+	EmitTimeoutConstants     bool               // 是否为设置了 (frontend.timeout_ms) 的方法导出超时常量
+	EmitOpsMap               bool               // 是否在 TS 中生成操作名到请求/响应类型的映射接口 XxxOps 及泛型入口 call(op, req)
+	Incremental              bool               // 是否不清空输出目录，按内容哈希清单跳过未变化的文件（适合 watch 模式反复生成）
+	FileExt                  string             // JS 输出文件的扩展名（如 .js、.mjs）
+	CacheGet                 int                // GET 方法内存缓存的有效期（秒），0 表示不缓存
+	ClientStyle              string             // service 方法的命名风格：method（service.get）、verb_upper（service.GET）或 unified（service.request('GET', ...)）
+	FetchBaseURL             string             // client=fetch 时请求路径的固定前缀（如 https://api.example.com）
+	EmitGroupTags            bool               // 是否在方法 JSDoc 中按路径推断的资源写入 @group 标签
+	Hooks                    string             // 为每个方法生成的 hook 类型：react-query、swr，为空时不生成
+	EmitEnumLabels           bool               // 是否为请求/响应中用到的枚举生成数值到注释文本的映射（xxxLabels）
+	StripSuffixes            []string           // 生成文件名/对象名前从服务名去掉的后缀，按顺序取第一个匹配的
+	BannerTool               string             // 文件头部 DO NOT EDIT 注释中的工具名
+	Banner                   bool               // 是否在生成文件头部写入 Code generated ... DO NOT EDIT. 注释及来源 proto 文件
+	PackageDirs              bool               // 是否按 proto 包名将服务文件写入子目录（shop.v1 -> shop/v1/）
+	ExportStyle              string             // API 对象的导出方式：object（单个对象）、named（每个方法具名导出，再组装为对象）
+	WriteResponse            bool               // 是否通过 CodeGeneratorResponse 交给 protoc 写出文件，而不是直接写入磁盘
+	ParamName                string             // 生成方法的请求参数名（默认 data）
+	EmitInterfaces           bool               // 是否在输出目录生成 types.ts 声明请求/响应消息的类型，代替从 ts-proto 导入
+	TimestampType            string             // emit_interfaces 时 google.protobuf.Timestamp 的 TS 类型：string（ISO 字符串）或 Date
+	Framework                string             // 为每个方法生成的前端框架封装：vue（组合式函数），为空时不生成
+	MethodFilter             nameFilter         // include_methods / exclude_methods：按 proto 方法名过滤生成的方法
+	ServiceFilter            nameFilter         // include_services / exclude_services：按 proto 服务名（含 Service 后缀）过滤生成的服务
+	PathPrefix               string             // 拼接在每个方法 HTTP 路径前的前缀（如 /api），不含末尾的 /
+	StripPathPrefix          string             // 从每个方法 HTTP 路径开头去掉的前缀（如 /internal），在拼接 path_prefix 之前处理
+	EmitPaths                bool               // 是否生成方法名到路径模板的映射常量 XxxPaths（保留 {id} 占位符）
+	SortMethods              bool               // 是否按方法名字母序排列生成的方法（默认按 proto 声明顺序）
+	Style                    codeStyle          // quote / indent / semi / trailing_comma：生成代码的引号、缩进、分号与末项逗号风格
+	LintIgnore               []string           // 文件头部写入的忽略指令：eslint（/* eslint-disable */）、prettier（// prettier-ignore）
+	Protocol                 string             // 调用协议：rest（默认，按 HTTP 注解调用 service）、connect（忽略 HTTP 注解，调用注入的 connect 客户端）
+	Streaming                string             // 流式方法的处理：skip（默认，跳过并给出警告）、stub（生成调用即 reject 的桩方法）
 }
 
 // 方法信息结构体
 type MethodInfo struct {
-	MethodName   string // 方法名称
-	HttpPath     string // HTTP 路径
-	HttpMethod   string // HTTP 方法（post, get等）
-	RequestType  string // 请求类型名称（用于 TS）
-	ResponseType string // 响应类型名称（用于 TS）
+	MethodName       string            // 方法名称
+	HttpPath         string            // HTTP 路径
+	HttpMethod       string            // HTTP 方法（post, get等）
+	RequestType      string            // 请求类型名称（用于 TS）
+	ResponseType     string            // 响应类型名称（用于 TS）
+	PathParams       []string          // 路径模板中绑定的字段（ts-proto 字段名，仅取顶层字段）
+	QueryFields      []string          // 映射为查询参数的顶层字段（ts-proto 字段名，GET 方法使用）
+	ClientMethod     string            // 覆盖的 service 调用方法名（如 longPoll），为空时使用 HttpMethod
+	Input            *protogen.Message // 请求消息（用于字段、枚举等进一步分析）
+	Output           *protogen.Message // 响应消息
+	Paginated        bool              // 是否为分页方法（请求含 page_token，响应含 next_page_token）
+	PathKeys         map[string]string // 路径变量字段路径（proto 字段名）-> 生成代码中的访问路径
+	PageTokenKey     string            // 分页方法请求中 page_token 的键名
+	NextPageTokenKey string            // 分页方法响应中 next_page_token 的键名
+	RequestExample   exampleObject     // 请求示例（仅开启 emit_examples 时填充）
+	ResponseExample  exampleObject     // 响应示例（仅开启 emit_examples 时填充）
+	PageItem         *protogen.Message // typed_pages 时分页响应的列表元素消息，非分页响应为 nil
+	PageItemType     string            // 列表元素类型名（Page<T> 中的 T）
+	PageItemsKey     string            // 分页响应中列表字段的键名
+	Defaults         exampleObject     // 请求默认值（仅开启 merge_defaults 时填充）
+	BodyKeys         []exampleEntry    // body_key_case 时需要改名的请求体顶层键（键名 -> 请求体键名）
+	Comment          string            // 方法注释（以 JSDoc 写在 API 对象的方法上方）
+	Timeout          int               // 通过 (frontend.timeout_ms) 设置的超时毫秒数，未设置时为 0
+	Deprecated       bool              // 方法是否设置了 option deprecated = true
+	NoRequest        bool              // 请求消息没有字段（如 google.protobuf.Empty），生成的方法不接收参数、不发送数据
+	StreamKind       string            // 流式类型：server、client、bidi，一元方法为空
+	BodyField        string            // HTTP 规则 body 指定的请求体字段的键名（如 book），为空时整个请求作为请求体
+	BodyQueryFields  []string          // body 指定字段时其余作为查询参数的顶层字段（不含路径参数）
 }
 
 // 服务信息结构体
 type ServiceInfo struct {
-	ServiceName     string              // 服务名称（去掉 Service 后缀）
-	ApiFileName     string              // API 文件名（如 productApi）
-	Methods         []MethodInfo        // 方法列表
-	ServiceImport   string              // service 导入路径
-	TypesImportPath string              // 类型定义导入路径前缀（如 @/api/proto-types）
-	TypeImports     map[string][]string // 需要导入的类型列表 (importPath -> sortedTypeNames)
+	ServiceName              string              // 服务名称（去掉 strip_suffix 后缀）
+	ApiFileName              string              // API 文件名（如 productApi）
+	Methods                  []MethodInfo        // 方法列表
+	ServiceImport            string              // service 导入路径
+	TypesImportPath          string              // 类型定义导入路径前缀（如 @/api/proto-types）
+	TypeImports              map[string][]string // 需要导入的类型列表 (importPath -> sortedTypeNames)
+	SplitQueryTypes          bool                // GET 方法是否使用单独的 XxxQuery 类型
+	PluginVersion            string              // 插件版本（非空时写入文件头部注释）
+	Enums                    []*protogen.Enum    // 需要生成互转函数的枚举（emit_enum_helpers）
+	EmitPathBuilders         bool                // 是否生成 XxxPath 路径构造函数
+	VerbResponses            map[string]string   // 按 HTTP 方法指定的响应处理（verb_response）
+	EmitInfiniteQueries      bool                // 是否生成 useInfiniteXxx hook
+	FullName                 string              // proto 服务全名（如 shop.v1.OrderService），用于提示信息
+	EmitRequestTypeNames     bool                // 是否生成 XxxRequestTypes 常量
+	ArrowStyle               string              // 方法箭头函数体风格（arrow_style）
+	RequestEnums             []*protogen.Enum    // 需要生成值常量的枚举（inline_request_enums）
+	EmitRegistryAugmentation bool                // 是否生成 declare global ApiRegistry 增强（仅 TS）
+	EmitExamples             bool                // 是否生成 XxxExamples 示例常量
+	EncodePathParams         bool                // 单段路径变量是否 encodeURIComponent
+	EmitResultUnion          bool                // 是否生成 XxxResult 联合类型（仅 TS）
+	ErrorTuple               bool                // 方法是否返回 [err, data] 元组
+	Comment                  string              // 服务注释（写在 API 对象上方）
+	EmitZod                  bool                // 是否生成 zod schema
+	UseJSONNames             bool                // 字段键是否使用 json_name（生成 schema 等需要按字段渲染的内容时使用）
+	MergeDefaults            bool                // 是否生成请求默认值并在调用时合并
+	Retry                    int                 // 失败后的最大重试次数
+	RetryStatuses            []int               // 需要重试的 HTTP 状态码
+	EmitConfigure            bool                // 是否生成 configure(cfg) 及模块级配置
+	StrictTypes              []strictType        // strict_null 时生成的 StrictXxx 类型
+	StrictNames              map[string]string   // ts-proto 类型名 -> StrictXxx 类型名
+	CallStyle                string              // service 调用风格（call_style）
+	BodyKeyCase              string              // 请求体顶层键名的大小写风格（body_key_case）
+	EmitAbortAll             bool                // 是否生成 abortAll() 及请求的 AbortController 登记
+	Package                  string              // 服务所在的 proto package，merge_by_package 时用于分组
+	Client                   string              // 请求方式：service 或 fetch
+	CompatArgs               bool                // 方法是否同时接受请求对象或位置参数
+	GetParams                string              // GET 请求数据的传法：data 或 query
+	EmitTimeoutConstants     bool                // 是否导出方法超时常量
+	EmitOpsMap               bool                // 是否生成 XxxOps 映射接口及 call(op, req)
+	CacheGet                 int                 // GET 方法内存缓存的有效期（秒）
+	ClientStyle              string              // service 方法的命名风格
+	FetchBaseURL             string              // client=fetch 时请求路径的固定前缀
+	EmitGroupTags            bool                // 是否写入 @group 标签
+	Hooks                    string              // 为每个方法生成的 hook 类型
+	LabelEnums               []*protogen.Enum    // 需要生成显示文本映射的枚举（emit_enum_labels）
+	Deprecated               bool                // 服务是否设置了 option deprecated = true
+	Banner                   string              // 文件头部 DO NOT EDIT 注释中的工具名，为空时不写入
+	SourceFile               string              // 服务所在的 proto 文件路径（写入文件头部注释），汇总文件为空
+	FileDir                  string              // package_dirs 时服务文件所在的子目录（如 shop/v1），否则为空
+	ExportStyle              string              // API 对象的导出方式：object、named
+	ParamName                string              // 生成方法的请求参数名
+	InterfaceRoots           []*protogen.Message // emit_interfaces 时需要在 types.ts 中声明的请求/响应消息
+	TimestampType            string              // emit_interfaces 时 Timestamp 的 TS 类型
+	Framework                string              // 为每个方法生成的前端框架封装
+	PathPrefix               string              // 拼接在 HTTP 路径前的前缀
+	StripPathPrefix          string              // 从 HTTP 路径开头去掉的前缀
+	EmitPaths                bool                // 是否生成 XxxPaths 常量
+	LintIgnore               []string            // 文件头部写入的忽略指令
+	Protocol                 string              // 调用协议：rest 或 connect
+	ServiceImportOverride    string              // 服务注释中 @frontend:service_import 指定的 service 导入，为空时使用配置
+	TrailingComma            string              // 多行对象/数组字面量末项后的逗号（trailing_comma）：true、false，为空时按各生成位置的默认
+}
+
+// version 插件版本，可在构建时通过 -ldflags "-X main.version=v1.2.3" 注入
+var version = ""
+
+// pluginVersion 返回插件版本：优先使用构建时注入的 version，其次使用 go install 记录的模块版本
+func pluginVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
 }
 
 func main() {
-	protogen.Options{}.Run(func(gen *protogen.Plugin) error {
-		// 解析插件参数
-		var param string
-		if gen.Request.Parameter != nil {
-			param = *gen.Request.Parameter
+	// 带命令行参数时按独立模式运行（读取 FileDescriptorSet），否则按 protoc 插件协议运行
+	if len(os.Args) > 1 {
+		if err := runStandalone(os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filepath.Base(os.Args[0]), err)
+			os.Exit(1)
 		}
+		return
+	}
+	protogen.Options{}.Run(generate)
+}
 
-		config, err := parsePluginOptions(param)
-		if err != nil {
-			return fmt.Errorf("解析插件参数失败: %v", err)
+// generate 插件主流程：解析参数、清空输出目录并为每个服务生成前端 API 文件
+func generate(gen *protogen.Plugin) error {
+	// 解析插件参数
+	var param string
+	if gen.Request.Parameter != nil {
+		param = *gen.Request.Parameter
+	}
+
+	config, err := parsePluginOptions(param)
+	if err != nil {
+		return fmt.Errorf("解析插件参数失败: %v", err)
+	}
+
+	if config.DumpConfig != "" {
+		if err := dumpConfig(config); err != nil {
+			return err
 		}
+	}
+
+	// 校验 service 导入能否解析（在清空输出目录之前进行）
+	if config.VerifyServiceImport && config.Client != "fetch" && config.Protocol != "connect" {
+		if err := verifyServiceImports(gen, config); err != nil {
+			return err
+		}
+	}
 
-		// 生成前清空各输出目录，确保只保留本次生成的文件（便于 proto 删除服务时移除旧 API）
+	// 生成前清空各输出目录，确保只保留本次生成的文件（便于 proto 删除服务时移除旧 API）
+	// 打包为 zip 或只校验时不写入输出目录，也就不需要清空
+	// incremental 模式下不清空，改为按清单跳过未变化的文件、删除不再生成的文件
+	// write_response 时经响应写出的目录由 protoc 管理，只清空需要直接写入磁盘的目录
+	if config.OutputZip == "" && !config.CheckOnly && !config.Incremental {
 		for _, outputPath := range config.OutputPaths {
+			if config.WriteResponse && isResponseDir(outputPath.Path) {
+				continue
+			}
 			if err := clearOutputDir(outputPath.Path); err != nil {
 				return fmt.Errorf("清空输出目录失败 %s: %v", outputPath.Path, err)
 			}
 		}
 		for _, outputPath := range config.OutputPathsJS {
+			if config.WriteResponse && isResponseDir(outputPath.Path) {
+				continue
+			}
 			if err := clearOutputDir(outputPath.Path); err != nil {
 				return fmt.Errorf("清空输出目录失败(JS) %s: %v", outputPath.Path, err)
 			}
 		}
+	}
+
+	out := newOutputWriter(config.OutputZip)
+	out.discard = config.CheckOnly
+	out.incremental = config.Incremental
+	out.style = config.Style
+	if config.Incremental && config.OutputZip == "" && !config.CheckOnly {
+		var dirs []string
+		for _, outputPath := range config.OutputPaths {
+			dirs = append(dirs, outputPath.Path)
+		}
+		for _, outputPath := range config.OutputPathsJS {
+			dirs = append(dirs, outputPath.Path)
+		}
+		out.loadManifests(dirs)
+	}
+	if config.WriteResponse {
+		out.gen = gen
+	}
+
+	var services []*ServiceInfo
+	var problems []string
+	fileOwners := make(map[string]*ServiceInfo)  // API 文件名 -> 生成它的服务
+	foldedNames := make(map[string]*ServiceInfo) // 小写后的 API 文件名 -> 服务，用于发现仅大小写不同的文件名
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
 
-		for _, f := range gen.Files {
-			if !f.Generate {
+		// 查找服务定义
+		for _, service := range f.Services {
+			// 跳过 include_services / exclude_services 过滤掉的服务（按 proto 服务名匹配，不去后缀）
+			if !config.ServiceFilter.allows(string(service.Desc.Name())) {
 				continue
 			}
+			// connect 模式不使用 HTTP 注解，无需校验
+			if config.Protocol != "connect" {
+				problems = append(problems, validateService(service, config)...)
+			}
+
+			// 生成前端 API 文件
+			info, err := generateFrontendApi(gen, f, service, config, out)
+			if err != nil {
+				return err
+			}
+			if info == nil {
+				continue
+			}
+			if config.Flatten || config.MergeByPackage {
+				services = append(services, info)
+				continue
+			}
+			filePath := serviceFilePath(*info)
+			if owner, ok := fileOwners[filePath]; ok {
+				// 如 Goods 与 GoodsService 去掉后缀后都生成 goodsApi：后写入的文件会覆盖前者
+				return fmt.Errorf("服务 %s（%s）与服务 %s（%s）生成的文件名相同: %s，后者会覆盖前者，请重命名其中一个服务或调整 strip_suffix",
+					owner.FullName, owner.SourceFile, info.FullName, info.SourceFile, filePath)
+			} else if prev, ok := foldedNames[strings.ToLower(filePath)]; ok {
+				// 如 APIService -> aPIApi 与 ApiService -> apiApi：在大小写不敏感的文件系统上会静默覆盖
+				return fmt.Errorf("服务 %s 生成的文件名 %s 与服务 %s 的 %s 仅大小写不同，在大小写不敏感的文件系统（macOS、Windows）上会互相覆盖，请重命名其中一个服务",
+					info.FullName, filePath, prev.FullName, serviceFilePath(*prev))
+			} else {
+				fileOwners[filePath] = info
+				foldedNames[strings.ToLower(filePath)] = info
+			}
+			services = append(services, info)
+		}
+	}
+
+	if config.Flatten {
+		problems = append(problems, flatProblems(services)...)
+	} else if config.MergeByPackage {
+		problems = append(problems, mergeProblems(groupByPackage(services))...)
+	}
+
+	// 只校验时有问题即失败；正常生成时只输出警告
+	if len(problems) > 0 {
+		if config.CheckOnly {
+			return fmt.Errorf("校验未通过:\n  %s", strings.Join(problems, "\n  "))
+		}
+		for _, problem := range problems {
+			logf("警告: %s", problem)
+		}
+	}
+
+	if config.Flatten && len(services) > 0 {
+		// 汇总文件导入所有服务的类型，同名类型无法共存，直接失败
+		if len(config.OutputPaths) > 0 {
+			if err := flatTypeConflicts(flatFileName+".ts", services); err != nil {
+				return err
+			}
+		}
+		if err := writeFlatFiles(services, config, out, flatFileName, "api"); err != nil {
+			return err
+		}
+	} else if config.MergeByPackage {
+		if len(config.OutputPaths) > 0 {
+			for _, group := range groupByPackage(services) {
+				if err := flatTypeConflicts(group.FileName+".ts", group.Services); err != nil {
+					return err
+				}
+			}
+		}
+		if err := writeMergedFiles(groupByPackage(services), config, out); err != nil {
+			return err
+		}
+	}
 
-			// 查找服务定义
-			for _, service := range f.Services {
-				// 生成前端 API 文件
-				if err := generateFrontendApi(gen, f, service, config); err != nil {
+	// emit_interfaces：TS 目录生成 types.ts，bundle_dts 时 JS 目录生成 api.d.ts 引用的 types.d.ts
+	if config.EmitInterfaces && len(services) > 0 {
+		code, err := generateInterfaces(services)
+		if err != nil {
+			return err
+		}
+		for _, outputPath := range config.OutputPaths {
+			if err := out.write(outputPath.Path, interfacesFileName+".ts", code); err != nil {
+				return err
+			}
+		}
+		if config.BundleDts {
+			for _, outputPath := range config.OutputPathsJS {
+				if err := out.write(outputPath.Path, interfacesFileName+".d.ts", code); err != nil {
 					return err
 				}
 			}
 		}
-		return nil
-	})
+	}
+
+	// 汇总入口：TS 目录同时重新导出类型，JS 目录只导出 API 对象
+	if config.GenerateIndex && !config.Flatten && !config.MergeByPackage && len(services) > 0 {
+		if len(config.OutputPaths) > 0 {
+			if err := indexTypeConflicts(config.IndexName+".ts", services); err != nil {
+				return err
+			}
+		}
+		for _, outputPath := range config.OutputPaths {
+			warnIndexShadowsServiceImport(config.IndexName, serviceImportFor(outputPath, config))
+			if err := out.write(outputPath.Path, config.IndexName+".ts", generateIndex(services, true)); err != nil {
+				return err
+			}
+		}
+		for _, outputPath := range config.OutputPathsJS {
+			warnIndexShadowsServiceImport(config.IndexName, serviceImportForJS(outputPath, config))
+			if err := out.write(outputPath.Path, config.IndexName+config.FileExt, generateIndex(services, false)); err != nil {
+				return err
+			}
+		}
+		// 生成 hook（React Query、SWR、Vue 组合式函数）时另外生成 hooks 汇总文件
+		if hooks := generateHooksIndex(services); hooks != nil {
+			for _, outputPath := range config.OutputPaths {
+				if err := out.write(outputPath.Path, "hooks.ts", hooks); err != nil {
+					return err
+				}
+			}
+			for _, outputPath := range config.OutputPathsJS {
+				if err := out.write(outputPath.Path, "hooks"+config.FileExt, hooks); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// package.json 片段：声明 ESM 及各模块的 exports
+	if config.EmitPackageJSON && len(services) > 0 {
+		for _, outputPath := range config.OutputPaths {
+			if err := out.write(outputPath.Path, "package.json", generatePackageJSON(packageExports(services, config, ".ts"))); err != nil {
+				return err
+			}
+		}
+		for _, outputPath := range config.OutputPathsJS {
+			if err := out.write(outputPath.Path, "package.json", generatePackageJSON(packageExports(services, config, config.FileExt))); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 为 JS 输出目录生成汇总声明文件 api.d.ts
+	if config.BundleDts && len(services) > 0 && len(config.OutputPathsJS) > 0 {
+		if err := bundleTypeConflicts(services); err != nil {
+			return err
+		}
+		code := generateBundleDts(services)
+		for _, outputPath := range config.OutputPathsJS {
+			if err := out.write(outputPath.Path, "api.d.ts", code); err != nil {
+				return err
+			}
+		}
+	}
+	return out.flush()
+}
+
+// runStandalone 独立模式：从 --descriptor_set_in 指定的 FileDescriptorSet 文件生成
+// 用法: protoc-gen-frontend-api --descriptor_set_in=api.pb --opt=output_paths=src/api [file.proto ...]
+// 未列出 proto 文件时，描述符集中的所有文件都参与生成
+func runStandalone(args []string) error {
+	fs := flag.NewFlagSet("protoc-gen-frontend-api", flag.ContinueOnError)
+	descriptorSetIn := fs.String("descriptor_set_in", "", "FileDescriptorSet 文件路径（如 protoc --descriptor_set_out 或 buf build -o 的产物）")
+	opt := fs.String("opt", "", "插件参数，与 --frontend-api_opt 相同")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *descriptorSetIn == "" {
+		return fmt.Errorf("缺少 --descriptor_set_in（本程序通常应由 protoc 调用）")
+	}
+	data, err := os.ReadFile(*descriptorSetIn)
+	if err != nil {
+		return fmt.Errorf("读取描述符集失败 %s: %v", *descriptorSetIn, err)
+	}
+	return generateFromDescriptorSet(data, *opt, fs.Args()...)
+}
+
+// generateFromDescriptorSet 以序列化的 FileDescriptorSet 为输入执行与插件模式相同的生成流程
+// filesToGenerate 为空时生成描述符集中的所有文件
+func generateFromDescriptorSet(data []byte, param string, filesToGenerate ...string) error {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("解析描述符集失败: %v", err)
+	}
+	if len(filesToGenerate) == 0 {
+		for _, f := range set.File {
+			filesToGenerate = append(filesToGenerate, f.GetName())
+		}
+	}
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: filesToGenerate,
+		Parameter:      proto.String(param),
+		ProtoFile:      set.File,
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		return err
+	}
+	if err := generate(gen); err != nil {
+		return err
+	}
+	return writeResponseFiles(gen)
 }
 
 // parsePluginOptions 解析插件参数
 func parsePluginOptions(param string) (*PluginConfig, error) {
 	config := &PluginConfig{
-		ServiceImport:   "./api",             // 默认 service 导入路径
-		ServiceImportJS: "",                  // 为空时 JS 使用 ServiceImport
-		TypesImportPath: "@/api/proto-types", // 默认类型定义导入路径
-		OutputPaths:     []OutputPathConfig{},
-		OutputPathsJS:   []OutputPathConfig{},
+		ServiceImport:    "./api",             // 默认 service 导入路径
+		ServiceImportJS:  "",                  // 为空时 JS 使用 ServiceImport
+		TypesImportPath:  "@/api/proto-types", // 默认类型定义导入路径
+		FirstAcronym:     "lower",             // 默认开头的缩写词整体小写（HTTP -> http）
+		EncodePathParams: true,                // 默认编码单段路径变量
+		IndexName:        "index",             // 默认汇总文件 index.ts / index.js
+		ArrowStyle:       "concise",           // 默认表达式体
+		CallStyle:        "args",              // 默认 service.verb(path, data)
+		OutputPaths:      []OutputPathConfig{},
+		OutputPathsJS:    []OutputPathConfig{},
+		Lang:             "js",
+		Client:           "service",
+		GetParams:        "data",
+		FileExt:          ".js",
+		ClientStyle:      "method",
+		StripSuffixes:    []string{"Service"},
+		ExportStyle:      "object",
+		ParamName:        "data",
+		TimestampType:    "string",
+		Protocol:         "rest",
+		Streaming:        "skip",
+		Banner:           true,
+		BannerTool:       "protoc-gen-frontend-api",
+		MethodClients:    map[string]string{},
+		VerbResponses:    map[string]string{},
 	}
 
-	if param == "" {
+	// 空白参数等同于未传参数
+	if strings.TrimSpace(param) == "" {
 		return config, nil
 	}
 
-	// 解析参数，格式: key1=value1,key2=value2
-	pairs := strings.Split(param, ",")
+	pairs, err := splitOptions(param)
+	if err != nil {
+		return nil, err
+	}
+	// options_file 中的参数先于内联参数应用，同名参数以内联参数为准
 	for _, pair := range pairs {
-		kv := strings.SplitN(pair, "=", 2)
-		if len(kv) != 2 {
+		if pair.Key == "options_file" && pair.Value != "" {
+			filePairs, err := loadOptionsFile(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(filePairs, pairs...)
+			break
+		}
+	}
+
+	for _, pair := range pairs {
+		key, value := pair.Key, pair.Value
+		// 值为空（key=）时保留默认值
+		if value == "" {
 			continue
 		}
-		key := strings.TrimSpace(kv[0])
-		value := strings.TrimSpace(kv[1])
 
 		switch key {
+		case "options_file":
+			// 已在上面加载
 		case "service_import":
 			config.ServiceImport = value
 		case "service_import_js":
@@ -127,12 +589,359 @@ func parsePluginOptions(param string) (*PluginConfig, error) {
 			config.OutputPaths = parseOutputPaths(value)
 		case "output_paths_js":
 			config.OutputPathsJS = parseOutputPaths(value)
+		case "output_dir":
+			config.OutputDir = value
+		case "split_query_types":
+			config.SplitQueryTypes = value == "true"
+		case "version_in_header":
+			config.VersionInHeader = value == "true"
+		case "streaming":
+			if err := validateStreaming(value); err != nil {
+				return nil, err
+			}
+			config.Streaming = value
+		case "protocol":
+			if err := validateProtocol(value); err != nil {
+				return nil, err
+			}
+			config.Protocol = value
+		case "lint_ignore":
+			// 格式: eslint;prettier（, 已用于分隔参数），true 表示两者都写入
+			ignores, err := parseLintIgnore(value)
+			if err != nil {
+				return nil, err
+			}
+			config.LintIgnore = ignores
+		case "semi":
+			if value != "true" && value != "false" {
+				return nil, fmt.Errorf("semi 只支持 true、false: %s", value)
+			}
+			config.Style.NoSemi = value == "false"
+		case "trailing_comma":
+			switch value {
+			case "true", "false":
+			case "none":
+				// 与 prettier 的 trailingComma: "none" 同义
+				value = "false"
+			default:
+				return nil, fmt.Errorf("trailing_comma 只支持 true、false、none: %s", value)
+			}
+			config.Style.TrailingComma = value
+		case "quote":
+			if value != "single" && value != "double" {
+				return nil, fmt.Errorf("quote 只支持 single、double: %s", value)
+			}
+			config.Style.Quote = value
+		case "indent":
+			indent, err := parseIndent(value)
+			if err != nil {
+				return nil, err
+			}
+			config.Style.Indent = indent
+		case "sort_methods":
+			config.SortMethods = value == "true"
+		case "emit_paths":
+			config.EmitPaths = value == "true"
+		case "strip_path_prefix":
+			config.StripPathPrefix = strings.TrimRight(value, "/")
+		case "path_prefix":
+			config.PathPrefix = strings.TrimRight(value, "/")
+		case "include_services":
+			// 格式: Goods*;Order*（, 已用于分隔参数）
+			patterns, err := parsePatterns(key, value)
+			if err != nil {
+				return nil, err
+			}
+			config.ServiceFilter.Include = patterns
+		case "exclude_services":
+			patterns, err := parsePatterns(key, value)
+			if err != nil {
+				return nil, err
+			}
+			config.ServiceFilter.Exclude = patterns
+		case "include_methods":
+			// 格式: Get*;List*（, 已用于分隔参数）
+			patterns, err := parsePatterns(key, value)
+			if err != nil {
+				return nil, err
+			}
+			config.MethodFilter.Include = patterns
+		case "exclude_methods":
+			patterns, err := parsePatterns(key, value)
+			if err != nil {
+				return nil, err
+			}
+			config.MethodFilter.Exclude = patterns
+		case "framework":
+			if err := validateFramework(value); err != nil {
+				return nil, err
+			}
+			config.Framework = value
+		case "timestamp_type":
+			if value != "string" && value != "Date" {
+				return nil, fmt.Errorf("timestamp_type 只支持 string、Date: %s", value)
+			}
+			config.TimestampType = value
+		case "emit_interfaces":
+			config.EmitInterfaces = value == "true"
+		case "param_name":
+			if err := validateParamName(value); err != nil {
+				return nil, err
+			}
+			config.ParamName = value
+		case "write_response":
+			config.WriteResponse = value == "true"
+		case "export_style":
+			if value != "object" && value != "named" {
+				return nil, fmt.Errorf("export_style 只支持 object、named: %s", value)
+			}
+			config.ExportStyle = value
+		case "package_dirs":
+			config.PackageDirs = value == "true"
+		case "banner":
+			config.Banner = value != "false"
+		case "banner_tool":
+			config.BannerTool = value
+		case "strip_suffix":
+			// 格式: Service;API;Rpc（, 已用于分隔参数）
+			config.StripSuffixes = parseSuffixList(value)
+		case "emit_enum_labels":
+			config.EmitEnumLabels = value == "true"
+		case "hooks":
+			if value != "react-query" && value != "swr" {
+				return nil, fmt.Errorf("hooks 只支持 react-query、swr: %s", value)
+			}
+			config.Hooks = value
+		case "emit_group_tags":
+			config.EmitGroupTags = value == "true"
+		case "fetch_base_url":
+			config.FetchBaseURL = strings.TrimSuffix(value, "/")
+		case "client_style":
+			if value != "method" && value != "verb_upper" && value != "unified" {
+				return nil, fmt.Errorf("client_style 只支持 method、verb_upper、unified: %s", value)
+			}
+			config.ClientStyle = value
+		case "cache_get":
+			ttl, err := strconv.Atoi(value)
+			if err != nil || ttl < 0 {
+				return nil, fmt.Errorf("cache_get 必须是非负整数（秒）: %s", value)
+			}
+			config.CacheGet = ttl
+		case "file_ext":
+			// 前导的 . 可省略：file_ext=mjs 与 file_ext=.mjs 等价
+			ext := strings.TrimPrefix(value, ".")
+			if ext == "" || strings.ContainsAny(ext, "/\\.") {
+				return nil, fmt.Errorf("file_ext 无效: %s", value)
+			}
+			config.FileExt = "." + ext
+		case "incremental":
+			config.Incremental = value == "true"
+		case "emit_ops_map":
+			config.EmitOpsMap = value == "true"
+		case "emit_timeout_constants":
+			config.EmitTimeoutConstants = value == "true"
+		case "get_params":
+			if value != "data" && value != "query" {
+				return nil, fmt.Errorf("get_params 只支持 data、query: %s", value)
+			}
+			config.GetParams = value
+		case "compat_args":
+			config.CompatArgs = value == "true"
+		case "client", "template":
+			// template 为 client 的别名（template=fetch 与 client=fetch 等价）
+			if err := validateClient(value); err != nil {
+				return nil, err
+			}
+			config.Client = value
+		case "merge_by_package":
+			config.MergeByPackage = value == "true"
+		case "lang":
+			if value != "ts" && value != "js" {
+				return nil, fmt.Errorf("lang 只支持 ts、js: %s", value)
+			}
+			config.Lang = value
+		case "emit_abort_all":
+			config.EmitAbortAll = value == "true"
+		case "body_key_case":
+			if err := validateBodyKeyCase(value); err != nil {
+				return nil, err
+			}
+			config.BodyKeyCase = value
+		case "call_style":
+			if value != "args" && value != "fluent" {
+				return nil, fmt.Errorf("call_style 只支持 args、fluent: %s", value)
+			}
+			config.CallStyle = value
+		case "strict_null":
+			config.StrictNull = value == "true"
+		case "emit_configure":
+			config.EmitConfigure = value == "true"
+		case "retry":
+			retry, err := strconv.Atoi(value)
+			if err != nil || retry < 0 {
+				return nil, fmt.Errorf("retry 必须为非负整数: %s", value)
+			}
+			config.Retry = retry
+		case "retry_statuses":
+			// 格式: 502;503;504（, 已用于分隔参数）
+			statuses, err := parseRetryStatuses(value)
+			if err != nil {
+				return nil, err
+			}
+			config.RetryStatuses = statuses
+		case "merge_defaults":
+			config.MergeDefaults = value == "true"
+		case "dump_config":
+			config.DumpConfig = value
+		case "emit_zod":
+			config.EmitZod = value == "true"
+		case "include_internal":
+			config.IncludeInternal = value == "true"
+		case "emit_package_json":
+			config.EmitPackageJSON = value == "true"
+		case "index_name":
+			if name := strings.TrimSuffix(strings.TrimSuffix(value, ".ts"), ".js"); name != "" {
+				config.IndexName = name
+			}
+		case "deep_comments":
+			config.DeepComments = value == "true"
+		case "generate_index", "emit_index":
+			// emit_index 为 generate_index 的别名
+			config.GenerateIndex = value == "true"
+		case "typed_pages":
+			config.TypedPages = value == "true"
+		case "emit_registry_augmentation":
+			config.EmitRegistryAugmentation = value == "true"
+		case "emit_examples":
+			config.EmitExamples = value == "true"
+		case "flatten":
+			config.Flatten = value == "true"
+		case "encode_path_params":
+			config.EncodePathParams = value != "false"
+		case "emit_result_union":
+			config.EmitResultUnion = value == "true"
+		case "error_tuple":
+			config.ErrorTuple = value == "true"
+		case "verify_service_import":
+			config.VerifyServiceImport = value == "true"
+		case "inline_request_enums":
+			config.InlineRequestEnums = value == "true"
+		case "arrow_style":
+			if value != "concise" && value != "block" {
+				return nil, fmt.Errorf("arrow_style 只支持 concise、block: %s", value)
+			}
+			config.ArrowStyle = value
+		case "emit_request_type_names":
+			config.EmitRequestTypeNames = value == "true"
+		case "check_only":
+			config.CheckOnly = value == "true"
+		case "use_json_names":
+			config.UseJSONNames = value == "true"
+		case "output_zip":
+			config.OutputZip = value
+		case "emit_infinite_queries":
+			config.EmitInfiniteQueries = value == "true"
+		case "first_acronym":
+			if value != "lower" && value != "first" && value != "preserve" {
+				return nil, fmt.Errorf("first_acronym 只支持 lower、first、preserve: %s", value)
+			}
+			config.FirstAcronym = value
+		case "verb_response":
+			// 格式: delete:void;get:data
+			for verb, mode := range parseKeyValueList(value) {
+				mode = strings.ToLower(mode)
+				if !isCustomKind(verb) {
+					return nil, fmt.Errorf("verb_response 的 HTTP 方法不合法: %s", verb)
+				}
+				if mode != "void" && mode != "data" && mode != "raw" {
+					return nil, fmt.Errorf("verb_response 只支持 void、data、raw: %s:%s", verb, mode)
+				}
+				config.VerbResponses[strings.ToLower(verb)] = mode
+			}
+		case "bundle_dts":
+			config.BundleDts = value == "true"
+		case "emit_path_builders":
+			config.EmitPathBuilders = value == "true"
+		case "default_verb":
+			// 与 custom 规则的 kind 一致：只含字母，作为 service 方法名调用
+			if value != "" && !isCustomKind(value) {
+				return nil, fmt.Errorf("default_verb 只能为字母组成的 HTTP 方法（如 post）: %s", value)
+			}
+			config.DefaultVerb = strings.ToLower(value)
+		case "emit_enum_helpers":
+			config.EmitEnumHelpers = value == "true"
+		case "method_client":
+			// 格式: Method:clientMethod;Service.Method:clientMethod
+			for k, v := range parseKeyValueList(value) {
+				config.MethodClients[k] = v
+			}
+		}
+	}
+
+	// output_dir 按 lang 作为 TS 或 JS 的唯一输出目录；对应的 output_paths / output_paths_js 优先，同时配置时忽略 output_dir 并给出警告
+	if config.OutputDir != "" {
+		paths, option := &config.OutputPaths, "output_paths"
+		if config.Lang == "js" {
+			paths, option = &config.OutputPathsJS, "output_paths_js"
+		}
+		if len(*paths) > 0 {
+			logf("警告: 同时配置了 output_dir 与 %s，以 %s 为准，忽略 output_dir=%s", option, option, config.OutputDir)
+		} else {
+			*paths = []OutputPathConfig{{Path: config.OutputDir}}
+		}
+	}
+
+	// 经响应写出的文件由 protoc 写入，插件无法比对已有文件，也不再自行打包
+	if config.WriteResponse && config.Incremental {
+		return nil, fmt.Errorf("write_response 不能与 incremental 同时使用")
+	}
+	if config.WriteResponse && config.OutputZip != "" {
+		return nil, fmt.Errorf("write_response 不能与 output_zip 同时使用")
+	}
+	// 两者生成的 useXxx 同名
+	if config.Framework == "vue" && config.Hooks != "" {
+		return nil, fmt.Errorf("framework=vue 不能与 hooks 同时使用")
+	}
+	// connect 客户端按服务注入，不经过 request / service，也无法汇总到跨服务的文件
+	if config.Protocol == "connect" {
+		if config.Client == "fetch" {
+			return nil, fmt.Errorf("protocol=connect 不能与 client=fetch 同时使用")
+		}
+		if config.Flatten || config.MergeByPackage {
+			return nil, fmt.Errorf("protocol=connect 不能与 flatten、merge_by_package 同时使用")
 		}
 	}
 
 	return config, nil
 }
 
+// splitOptions 解析内联参数，格式: key1=value1,key2=value2
+// 空项（末尾或连续的逗号）忽略；缺少 = 或参数名为空时报错
+func splitOptions(param string) ([]optionPair, error) {
+	var pairs []optionPair
+	for _, pair := range strings.Split(param, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("参数 %q 缺少 =（格式为 key=value，一个参数有多个值时用 ; 分隔）", strings.TrimSpace(pair))
+		}
+		key := strings.TrimSpace(kv[0])
+		if key == "" {
+			return nil, fmt.Errorf("参数 %q 缺少参数名", strings.TrimSpace(pair))
+		}
+		pairs = append(pairs, optionPair{Key: key, Value: strings.TrimSpace(kv[1])})
+	}
+	return pairs, nil
+}
+
+// isResponseDir 判断输出目录是否位于生成根目录（当前目录）内，即 write_response 时其中的文件经响应写出
+func isResponseDir(dir string) bool {
+	_, ok := responsePath(dir)
+	return ok
+}
+
 // clearOutputDir 清空输出目录：删除目录内所有内容后重建该目录
 // 若目录不存在，则什么也不做、不报错
 func clearOutputDir(dir string) error {
@@ -151,6 +960,33 @@ func clearOutputDir(dir string) error {
 	return os.MkdirAll(dir, 0755)
 }
 
+// serviceImportFor 返回 TS 输出路径使用的 service 导入：优先使用路径上的配置，否则使用全局 service_import
+func serviceImportFor(outputPath OutputPathConfig, config *PluginConfig) string {
+	if outputPath.ServiceImport != "" {
+		return outputPath.ServiceImport
+	}
+	return config.ServiceImport
+}
+
+// serviceImportOf 返回服务使用的 service 导入：服务注释中的 @frontend:service_import 优先，否则为输出路径对应的配置
+func serviceImportOf(info ServiceInfo, configured string) string {
+	if info.ServiceImportOverride != "" {
+		return info.ServiceImportOverride
+	}
+	return configured
+}
+
+// serviceImportForJS 返回 JS 输出路径使用的 service 导入：路径上的配置 > service_import_js > service_import
+func serviceImportForJS(outputPath OutputPathConfig, config *PluginConfig) string {
+	if outputPath.ServiceImport != "" {
+		return outputPath.ServiceImport
+	}
+	if config.ServiceImportJS != "" {
+		return config.ServiceImportJS
+	}
+	return config.ServiceImport
+}
+
 // parseOutputPaths 解析输出路径配置
 func parseOutputPaths(value string) []OutputPathConfig {
 	var paths []OutputPathConfig
@@ -159,8 +995,11 @@ func parseOutputPaths(value string) []OutputPathConfig {
 		if pathStr == "" {
 			continue
 		}
-		// 检查是否包含 service_import，格式: path:import
+		// 检查是否包含 service_import，格式: path:import（路径为空的项忽略，import 为空时使用全局的）
 		if parts := strings.SplitN(pathStr, ":", 2); len(parts) == 2 {
+			if strings.TrimSpace(parts[0]) == "" {
+				continue
+			}
 			paths = append(paths, OutputPathConfig{
 				Path:          strings.TrimSpace(parts[0]),
 				ServiceImport: strings.TrimSpace(parts[1]),
@@ -176,29 +1015,192 @@ func parseOutputPaths(value string) []OutputPathConfig {
 	return paths
 }
 
+// dumpConfig 将解析后的完整配置（含默认值）以缩进 JSON 写入 dump_config 指定的文件，
+// 键为参数名（与 options_file 相同），导出的文件可直接作为 options_file 使用
+func dumpConfig(config *PluginConfig) error {
+	data, err := marshalOptions(config)
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %v", err)
+	}
+	if err := os.WriteFile(config.DumpConfig, data, 0644); err != nil {
+		return fmt.Errorf("写入配置文件失败 %s: %v", config.DumpConfig, err)
+	}
+	return nil
+}
+
+// parseKeyValueList 解析 key1:value1;key2:value2 格式的映射，忽略空项和缺少 : 的项
+func parseKeyValueList(value string) map[string]string {
+	result := make(map[string]string)
+	for _, item := range strings.Split(value, ";") {
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		k, v := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if k != "" && v != "" {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// parseSuffixList 解析 ; 分隔的后缀列表，忽略空项
+func parseSuffixList(value string) []string {
+	var suffixes []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			suffixes = append(suffixes, item)
+		}
+	}
+	return suffixes
+}
+
+// trimServiceSuffix 去掉服务名中第一个匹配的后缀（例如：GoodsService -> Goods，GoodsAPI -> Goods）
+// 都不匹配或去掉后为空时使用完整服务名
+func trimServiceSuffix(name string, suffixes []string) string {
+	for _, suffix := range suffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
+
+// packageDir 返回 package_dirs 时服务文件所在的子目录（proto 包名的 . 换成 /，如 shop.v1 -> shop/v1），未开启或没有包名时为空
+func packageDir(file *protogen.File, config *PluginConfig) string {
+	if !config.PackageDirs {
+		return ""
+	}
+	return strings.ReplaceAll(string(file.Desc.Package()), ".", "/")
+}
+
+// serviceFilePath 返回服务文件相对输出目录的路径（不含扩展名，如 shop/v1/orderApi）
+func serviceFilePath(data ServiceInfo) string {
+	if data.FileDir == "" {
+		return data.ApiFileName
+	}
+	return data.FileDir + "/" + data.ApiFileName
+}
+
+// relativeToDir 将相对输出目录的导入路径（./ 或 ../ 开头）改写为相对子目录 dir 的路径（如 dir 为 shop/v1 时 ./api -> ../../api）
+// 别名、包名等非相对路径原样返回
+func relativeToDir(importPath, dir string) string {
+	if dir == "" || !(strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../")) {
+		return importPath
+	}
+	up := strings.Repeat("../", strings.Count(dir, "/")+1)
+	return up + strings.TrimPrefix(importPath, "./")
+}
+
 // generateFrontendApi 生成前端 API 文件
-func generateFrontendApi(gen *protogen.Plugin, file *protogen.File, service *protogen.Service, config *PluginConfig) error {
-	// 服务名称（去掉 Service 后缀）
-	serviceName := strings.TrimSuffix(string(service.Desc.Name()), "Service")
+// 返回该服务的模板数据（供汇总类输出使用），服务没有可生成的方法时返回 nil
+func generateFrontendApi(gen *protogen.Plugin, file *protogen.File, service *protogen.Service, config *PluginConfig, out *outputWriter) (*ServiceInfo, error) {
+	// 服务名称（去掉 strip_suffix 配置的后缀，默认 Service）
+	serviceName := trimServiceSuffix(string(service.Desc.Name()), config.StripSuffixes)
 
 	// 生成 API 文件名（例如：GoodsService -> goodsApi）
-	apiFileName := toCamelCase(serviceName) + "Api"
+	apiFileName := toCamelCaseWithPolicy(serviceName, config.FirstAcronym) + "Api"
 
 	// 提取方法信息
 	var methods []MethodInfo
+	takenNames := make(map[string]bool) // 服务中已占用的方法名，避免 additional_bindings 生成的方法重名
 	for _, method := range service.Methods {
-		// 只处理有 HTTP 注解的方法
-		if httpRule := extractHttpRule(method); httpRule != nil {
+		takenNames[string(method.Desc.Name())] = true
+	}
+	for _, method := range service.Methods {
+		// 跳过仅内部可见的方法（google.api.method_visibility 的 restriction 含 INTERNAL）
+		if !config.IncludeInternal && isInternalMethod(method) {
+			continue
+		}
+		// 跳过通过 option (frontend.ignore) = true 排除的方法
+		if isIgnoredMethod(method) {
+			continue
+		}
+		// 跳过 include_methods / exclude_methods 过滤掉的方法
+		if !config.MethodFilter.allows(string(method.Desc.Name())) {
+			continue
+		}
+		// connect 模式忽略 HTTP 注解，生成服务的所有方法
+		rules := []*HttpRule{connectRule(string(service.Desc.FullName()), string(method.Desc.Name()))}
+		if config.Protocol != "connect" {
+			// 只处理有 HTTP 注解的方法；additional_bindings 中的每条绑定另外生成一个方法
+			httpRule := extractHttpRule(method, config.DefaultVerb)
+			if httpRule == nil {
+				continue
+			}
+			rules = append([]*HttpRule{httpRule}, extractAdditionalBindings(method, config.DefaultVerb)...)
+		}
+		// 流式方法无法以一元请求调用，默认跳过；streaming=stub 时生成桩方法
+		kind := streamKind(method)
+		if kind != "" && config.Streaming != "stub" {
+			logf("警告: %s 为%s方法，不能以一元请求调用，已跳过（streaming=stub 可生成桩方法）", method.Desc.FullName(), streamKindLabel(kind))
+			continue
+		}
+		for i, httpRule := range rules {
+			methodName := string(method.Desc.Name())
+			if i > 0 {
+				methodName = bindingMethodName(methodName, httpRule, i, takenNames)
+			}
+			if httpRule.Fallback != "" {
+				logf("%s 的 HTTP 规则无法识别（%s），回退使用 %s", method.Desc.FullName(), httpRule.Fallback, httpRule.Method)
+			}
+			if _, ok := stripPathPrefix(httpRule.Path, config.StripPathPrefix); !ok {
+				logf("警告: %s 的路径 %s 不以 strip_path_prefix %s 开头，保持不变", method.Desc.FullName(), httpRule.Path, config.StripPathPrefix)
+			}
 			// 获取请求和响应类型名称
-			requestType := string(method.Input.Desc.Name())
-			responseType := string(method.Output.Desc.Name())
+			requestType := messageTSType(method.Input)
+			responseType := messageTSType(method.Output)
 
 			methodInfo := MethodInfo{
-				MethodName:   string(method.Desc.Name()),
+				MethodName:   methodName,
+				Comment:      strings.TrimSpace(string(method.Comments.Leading)),
+				Timeout:      methodTimeout(method),
+				Deprecated:   isDeprecatedMethod(method),
 				HttpPath:     httpRule.Path,
 				HttpMethod:   strings.ToLower(httpRule.Method),
 				RequestType:  requestType,
 				ResponseType: responseType,
+				Input:        method.Input,
+				Output:       method.Output,
+				NoRequest:    isEmptyMessage(method.Input),
+				StreamKind:   kind,
+			}
+			methodInfo.PathParams, methodInfo.QueryFields, methodInfo.PathKeys = classifyFields(method.Input, httpRule.Path, config.UseJSONNames)
+			if isPaginated(method.Input, method.Output) {
+				methodInfo.Paginated = true
+				methodInfo.PageTokenKey = fieldKeyPath(method.Input, "page_token", config.UseJSONNames)
+				methodInfo.NextPageTokenKey = fieldKeyPath(method.Output, "next_page_token", config.UseJSONNames)
+			}
+			if config.TypedPages {
+				if items := pageItemsOf(method.Output); items != nil {
+					methodInfo.PageItem = items
+					methodInfo.PageItemType = string(items.Desc.Name())
+					methodInfo.PageItemsKey = fieldKeyPath(method.Output, "items", config.UseJSONNames)
+					methodInfo.NextPageTokenKey = fieldKeyPath(method.Output, "next_page_token", config.UseJSONNames)
+				}
+			}
+			// body 指定字段时只发送该字段，其余未绑定到路径的字段作为查询参数
+			body := method.Input
+			if field := bodyField(method.Input, httpRule.Body); field != nil && hasBody(methodInfo) {
+				methodInfo.BodyField = fieldKey(field, config.UseJSONNames)
+				methodInfo.BodyQueryFields = bodyQueryFields(methodInfo.QueryFields, methodInfo.BodyField)
+				body = field.Message
+			}
+			if config.BodyKeyCase != "" && hasBody(methodInfo) && body != nil {
+				methodInfo.BodyKeys = buildBodyKeys(body, config.BodyKeyCase, config.UseJSONNames)
+			}
+			if config.MergeDefaults {
+				methodInfo.Defaults = buildDefaults(method.Input, config.UseJSONNames)
+			}
+			if config.EmitExamples {
+				methodInfo.RequestExample = buildExample(method.Input, config.UseJSONNames, config.TimestampType)
+				methodInfo.ResponseExample = buildExample(method.Output, config.UseJSONNames, config.TimestampType)
+			}
+			// 按方法覆盖 service 调用（优先匹配 Service.Method，其次 Method）
+			if clientMethod, ok := config.MethodClients[string(service.Desc.Name())+"."+methodInfo.MethodName]; ok {
+				methodInfo.ClientMethod = clientMethod
+			} else if clientMethod, ok := config.MethodClients[methodInfo.MethodName]; ok {
+				methodInfo.ClientMethod = clientMethod
 			}
 			methods = append(methods, methodInfo)
 		}
@@ -206,172 +1208,548 @@ func generateFrontendApi(gen *protogen.Plugin, file *protogen.File, service *pro
 
 	// 如果没有方法，跳过生成
 	if len(methods) == 0 {
-		return nil
+		return nil, nil
+	}
+
+	// sort_methods：按方法名排序，proto 中调整声明顺序不影响生成结果
+	if config.SortMethods {
+		sort.SliceStable(methods, func(i, j int) bool {
+			return methods[i].MethodName < methods[j].MethodName
+		})
 	}
 
 	// 收集所有使用的类型及其所在的 proto 文件
 	// 用于生成正确的 import 语句
-	typeImports := collectTypeImports(gen, service, methods)
+	typeImports := collectTypeImports(gen, service, methods, config.VerbResponses, config.Protocol)
 
-	// 收集所有输出路径配置（只使用 TS 路径）
-	allOutputPaths := config.OutputPaths
+	// strict_null：响应类型改为 StrictXxx，需要额外导入被改写的嵌套消息类型
+	var strictTypes []strictType
+	if config.StrictNull {
+		strictTypes = collectStrictTypes(methods, config.VerbResponses, config.UseJSONNames)
+		addStrictImports(typeImports, strictTypes)
+	}
 
-	// 如果没有配置输出路径，跳过生成
-	if len(allOutputPaths) == 0 {
-		return nil
+	// 收集请求/响应中用到的枚举，用于生成互转函数
+	var enums []*protogen.Enum
+	if config.EmitEnumHelpers {
+		enums = collectEnums(methods)
+	}
+	var requestEnums []*protogen.Enum
+	if config.InlineRequestEnums {
+		requestEnums = collectRequestEnums(methods)
+	}
+	var labelEnums []*protogen.Enum
+	if config.EmitEnumLabels {
+		labelEnums = collectEnums(methods)
 	}
 
-	// 对每个路径都生成文件
-	for _, outputPathConfig := range allOutputPaths {
-		// 确定该路径使用的 service_import
-		serviceImport := outputPathConfig.ServiceImport
-		if serviceImport == "" {
-			serviceImport = config.ServiceImport
-		}
+	// 各输出路径共用的模板数据，service_import 按路径单独确定
+	info := &ServiceInfo{
+		ServiceName:              serviceName,
+		FullName:                 string(service.Desc.FullName()),
+		ApiFileName:              apiFileName,
+		Methods:                  methods,
+		TypesImportPath:          config.TypesImportPath,
+		TypeImports:              typeImports,
+		SplitQueryTypes:          config.SplitQueryTypes,
+		PluginVersion:            headerVersion(config),
+		Enums:                    enums,
+		EmitPathBuilders:         config.EmitPathBuilders,
+		VerbResponses:            config.VerbResponses,
+		EmitInfiniteQueries:      config.EmitInfiniteQueries,
+		EmitRequestTypeNames:     config.EmitRequestTypeNames,
+		ArrowStyle:               config.ArrowStyle,
+		RequestEnums:             requestEnums,
+		EmitRegistryAugmentation: config.EmitRegistryAugmentation,
+		EmitExamples:             config.EmitExamples,
+		EncodePathParams:         config.EncodePathParams,
+		EmitResultUnion:          config.EmitResultUnion,
+		ErrorTuple:               config.ErrorTuple,
+		EmitZod:                  config.EmitZod,
+		MergeDefaults:            config.MergeDefaults,
+		Retry:                    config.Retry,
+		EmitConfigure:            config.EmitConfigure,
+		CallStyle:                config.CallStyle,
+		BodyKeyCase:              config.BodyKeyCase,
+		EmitAbortAll:             config.EmitAbortAll,
+		StrictTypes:              strictTypes,
+		StrictNames:              strictTypeNames(strictTypes),
+		RetryStatuses:            config.RetryStatuses,
+		UseJSONNames:             config.UseJSONNames,
+		Package:                  string(file.Desc.Package()),
+		Client:                   config.Client,
+		CompatArgs:               config.CompatArgs,
+		GetParams:                config.GetParams,
+		EmitTimeoutConstants:     config.EmitTimeoutConstants,
+		EmitOpsMap:               config.EmitOpsMap,
+		CacheGet:                 config.CacheGet,
+		ClientStyle:              config.ClientStyle,
+		FetchBaseURL:             config.FetchBaseURL,
+		EmitGroupTags:            config.EmitGroupTags,
+		Hooks:                    config.Hooks,
+		LabelEnums:               labelEnums,
+		Deprecated:               isDeprecatedService(service),
+		Banner:                   headerBanner(config),
+		SourceFile:               file.Desc.Path(),
+		FileDir:                  packageDir(file, config),
+		ExportStyle:              config.ExportStyle,
+		ParamName:                config.ParamName,
+		TimestampType:            config.TimestampType,
+		Framework:                config.Framework,
+		PathPrefix:               config.PathPrefix,
+		StripPathPrefix:          config.StripPathPrefix,
+		EmitPaths:                config.EmitPaths,
+		LintIgnore:               config.LintIgnore,
+		Protocol:                 config.Protocol,
+		TrailingComma:            config.Style.TrailingComma,
+	}
 
-		// 准备模板数据
-		data := ServiceInfo{
-			ServiceName:     serviceName,
-			ApiFileName:     apiFileName,
-			Methods:         methods,
-			ServiceImport:   serviceImport,
-			TypesImportPath: config.TypesImportPath,
-			TypeImports:     typeImports,
+	info.Comment = getServiceComment(file, service, config.DeepComments)
+	info.ServiceImportOverride, info.Comment = parseServiceImportDirective(info.Comment)
+
+	// emit_interfaces：类型改为从输出目录中生成的 types.ts 导入，不再依赖 ts-proto
+	if config.EmitInterfaces {
+		info.InterfaceRoots = interfaceRoots(methods, config.VerbResponses)
+		info.TypeImports = localTypeImports(info.TypeImports)
+		info.TypesImportPath = "./" + interfacesFileName
+	}
+
+	// flatten / merge_by_package 模式下不按服务写文件，由 generate 汇总后统一写出
+	if config.Flatten || config.MergeByPackage {
+		if info.ServiceImportOverride != "" {
+			logf("警告: %s 的 %s 在 flatten、merge_by_package 时不生效，汇总文件使用配置的 service_import", info.FullName, serviceImportDirective)
 		}
+		return info, nil
+	}
+
+	// 对每个 TS 路径都生成文件（未配置 output_paths 时不生成 TS，仍继续生成 JS）
+	for _, outputPathConfig := range config.OutputPaths {
+		// 确定该路径使用的 service_import
+		data := *info
+		data.ServiceImport = relativeToDir(serviceImportOf(*info, serviceImportFor(outputPathConfig, config)), info.FileDir)
+		data.TypesImportPath = relativeToDir(data.TypesImportPath, info.FileDir)
 
 		// 生成 TypeScript 代码
 		code := generateTypeScriptCode(data)
-		fileName := toCamelCase(serviceName) + "Api.ts"
+		fileName := serviceFilePath(data) + ".ts"
 
 		// 若输出目录不存在，跳过该路径，不报错
-		if _, err := os.Stat(outputPathConfig.Path); err != nil {
-			if os.IsNotExist(err) {
-				continue
+		if err := out.write(outputPathConfig.Path, fileName, code); err != nil {
+			return nil, err
+		}
+	}
+
+	// 按 output_paths_js 生成 JS 接口（无类型 import，(data) => service.{method}('path', data)）
+	for _, outputPathConfig := range config.OutputPathsJS {
+		data := *info
+		data.ServiceImport = relativeToDir(serviceImportOf(*info, serviceImportForJS(outputPathConfig, config)), info.FileDir)
+		code := generateJavaScriptCode(data)
+		fileName := serviceFilePath(data) + config.FileExt
+		if err := out.write(outputPathConfig.Path, fileName, code); err != nil {
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
+// extractHttpRule 从方法中提取 HTTP 规则
+// defaultVerb 非空时，无法识别的规则（如 kind 不是字母的 custom）在能取到路径的情况下回退使用该 HTTP 方法
+func extractHttpRule(method *protogen.Method, defaultVerb string) *HttpRule {
+	return httpRuleFromPattern(httpRuleOf(method), defaultVerb)
+}
+
+// httpRuleFromPattern 从 google.api.http 注解（或其中的一条 additional_bindings）中提取 HTTP 方法、路径及 body
+func httpRuleFromPattern(rule *annotations.HttpRule, defaultVerb string) *HttpRule {
+	r := httpPattern(rule, defaultVerb)
+	if r != nil {
+		r.Body = rule.GetBody()
+	}
+	return r
+}
+
+// httpPattern 从注解的 pattern 中提取 HTTP 方法与路径
+func httpPattern(rule *annotations.HttpRule, defaultVerb string) *HttpRule {
+	if rule == nil {
+		return nil
+	}
+
+	// 优先使用 post/get/put/delete/patch 中的路径
+	// 通过 GetPattern() 取 oneof，并按具体类型访问不同的 HTTP 方法
+	switch v := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Post:
+		if len(v.Post) > 0 {
+			return &HttpRule{
+				Method: "post",
+				Path:   v.Post,
+			}
+		}
+	case *annotations.HttpRule_Get:
+		if len(v.Get) > 0 {
+			return &HttpRule{
+				Method: "get",
+				Path:   v.Get,
+			}
+		}
+	case *annotations.HttpRule_Put:
+		if len(v.Put) > 0 {
+			return &HttpRule{
+				Method: "put",
+				Path:   v.Put,
+			}
+		}
+	case *annotations.HttpRule_Delete:
+		if len(v.Delete) > 0 {
+			return &HttpRule{
+				Method: "delete",
+				Path:   v.Delete,
 			}
-			return fmt.Errorf("检查输出目录失败 %s: %v", outputPathConfig.Path, err)
 		}
+	case *annotations.HttpRule_Patch:
+		if len(v.Patch) > 0 {
+			return &HttpRule{
+				Method: "patch",
+				Path:   v.Patch,
+			}
+		}
+	case *annotations.HttpRule_Custom:
+		// custom 的 kind（如 HEAD、OPTIONS）小写后作为 HTTP 方法，即调用 service.head / service.options
+		if v.Custom != nil && len(v.Custom.Path) > 0 && isCustomKind(v.Custom.Kind) {
+			return &HttpRule{
+				Method: strings.ToLower(v.Custom.Kind),
+				Path:   v.Custom.Path,
+			}
+		}
+		if defaultVerb != "" && v.Custom != nil && len(v.Custom.Path) > 0 {
+			return &HttpRule{
+				Method:   defaultVerb,
+				Path:     v.Custom.Path,
+				Fallback: "custom " + strconv.Quote(v.Custom.Kind),
+			}
+		}
+	}
+
+	return nil
+}
+
+// isCustomKind 判断 custom 规则的 kind 能否直接作为 HTTP 方法名（非空且只含 ASCII 字母）
+func isCustomKind(kind string) bool {
+	if kind == "" {
+		return false
+	}
+	for _, r := range kind {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// httpRuleOf 返回方法上的 google.api.http 注解，未设置时返回 nil
+func httpRuleOf(method *protogen.Method) *annotations.HttpRule {
+	// 获取方法的选项
+	options, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	if !ok || options == nil {
+		return nil
+	}
+
+	// 获取 HTTP 注解
+	rule, ok := proto.GetExtension(options, annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil || rule.GetPattern() == nil {
+		return nil
+	}
+	return rule
+}
+
+// isDeprecatedMethod 判断方法是否设置了 option deprecated = true
+func isDeprecatedMethod(method *protogen.Method) bool {
+	options, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	return ok && options.GetDeprecated()
+}
+
+// isDeprecatedService 判断服务是否设置了 option deprecated = true
+func isDeprecatedService(service *protogen.Service) bool {
+	options, ok := service.Desc.Options().(*descriptorpb.ServiceOptions)
+	return ok && options.GetDeprecated()
+}
+
+// isInternalMethod 判断方法是否通过 google.api.method_visibility 标记为 INTERNAL
+// restriction 可为逗号分隔的多个标签（如 "INTERNAL, PREVIEW"），读取方式与 HTTP 注解相同
+func isInternalMethod(method *protogen.Method) bool {
+	options, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	if !ok || options == nil {
+		return false
+	}
+	rule, ok := proto.GetExtension(options, visibility.E_MethodVisibility).(*visibility.VisibilityRule)
+	if !ok || rule == nil {
+		return false
+	}
+	for _, label := range strings.Split(rule.GetRestriction(), ",") {
+		if strings.TrimSpace(label) == "INTERNAL" {
+			return true
+		}
+	}
+	return false
+}
+
+// logf 向 stderr 输出提示信息（protoc 会原样展示插件的 stderr）
+func logf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "protoc-gen-frontend-api: "+format+"\n", args...)
+}
+
+// HttpRule HTTP 规则结构
+type HttpRule struct {
+	Method   string
+	Path     string
+	Fallback string // 回退使用 default_verb 时记录原规则（用于提示），否则为空
+	Body     string // 注解的 body：* 表示整个请求，字段名表示只以该字段作为请求体，为空表示没有请求体
+}
+
+// toCamelCase 将名称转为小写开头，开头的缩写词整体小写（例如：Goods -> goods，HTTPService -> httpService，SMS -> sms）
+func toCamelCase(s string) string {
+	if run := leadingAcronym(s); run >= 2 {
+		return strings.ToLower(s[:run]) + s[run:]
+	}
+	return lowerFirst(s)
+}
+
+// lowerFirst 只将首字母转为小写（例如：HTTPService -> hTTPService）
+func lowerFirst(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
 
-		fullPath := filepath.Join(outputPathConfig.Path, fileName)
-		if err := os.WriteFile(fullPath, code, 0644); err != nil {
-			return fmt.Errorf("写入文件失败 %s: %v", fullPath, err)
-		}
+// leadingAcronym 返回名称开头缩写词的长度（开头连续大写字母的个数）
+// 缩写词后紧跟小写字母时，最后一个大写字母属于下一个单词（HTTPService -> HTTP + Service）
+func leadingAcronym(s string) int {
+	run := 0
+	for run < len(s) && s[run] >= 'A' && s[run] <= 'Z' {
+		run++
+	}
+	if run < len(s) && s[run] >= 'a' && s[run] <= 'z' {
+		run--
 	}
+	return run
+}
 
-	// 按 output_paths_js 生成 JS 接口（无类型 import，(data) => service.{method}('path', data)）
-	for _, outputPathConfig := range config.OutputPathsJS {
-		serviceImport := outputPathConfig.ServiceImport
-		if serviceImport == "" {
-			serviceImport = config.ServiceImportJS
+// snakeToCamel 将 proto 字段名转为 ts-proto 默认使用的 camelCase（例如：goods_id -> goodsId）
+func snakeToCamel(s string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range s {
+		if r == '_' {
+			upper = true
+			continue
 		}
-		if serviceImport == "" {
-			serviceImport = config.ServiceImport
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
 		}
-		data := ServiceInfo{
-			ServiceName:   serviceName,
-			ApiFileName:   apiFileName,
-			Methods:       methods,
-			ServiceImport: serviceImport,
+		upper = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// pathVar 路径模板中的变量
+type pathVar struct {
+	FieldPath string // 字段路径（如 book.id）
+	Pattern   string // 匹配模式（如 shelves/*、**），未指定时为空
+}
+
+// parsePathTemplate 解析 HTTP 路径模板，返回变量之间的字面量片段和变量列表（len(literals) == len(vars)+1）
+// 例如: /v1/{parent=shelves/*}/books/{book.id} -> [/v1/ /books/ ""] 和 [parent(shelves/*) book.id]
+func parsePathTemplate(path string) (literals []string, vars []pathVar) {
+	for {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			break
 		}
-		code := generateJavaScriptCode(data)
-		fileName := toCamelCase(serviceName) + "Api.js"
-		if _, err := os.Stat(outputPathConfig.Path); err != nil {
-			if os.IsNotExist(err) {
-				continue
-			}
-			return fmt.Errorf("检查输出目录失败(JS) %s: %v", outputPathConfig.Path, err)
+		end := strings.Index(path[start:], "}")
+		if end < 0 {
+			break
 		}
-		fullPath := filepath.Join(outputPathConfig.Path, fileName)
-		if err := os.WriteFile(fullPath, code, 0644); err != nil {
-			return fmt.Errorf("写入文件失败(JS) %s: %v", fullPath, err)
+		v := pathVar{FieldPath: path[start+1 : start+end]}
+		// {name=pattern} 等号前为字段路径，等号后为匹配模式
+		if i := strings.Index(v.FieldPath, "="); i >= 0 {
+			v.FieldPath, v.Pattern = v.FieldPath[:i], strings.TrimSpace(v.FieldPath[i+1:])
 		}
+		v.FieldPath = strings.TrimSpace(v.FieldPath)
+		literals = append(literals, path[:start])
+		vars = append(vars, v)
+		path = path[start+end+1:]
 	}
-
-	return nil
+	return append(literals, path), vars
 }
 
-// extractHttpRule 从方法中提取 HTTP 规则
-func extractHttpRule(method *protogen.Method) *HttpRule {
-	// 获取方法的选项
-	options, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
-	if !ok || options == nil {
-		return nil
+// methodPath 返回生成代码中请求的路径模板：先去掉 strip_path_prefix，再在前面拼接 path_prefix，连接处只保留一个 /
+// （例如：/api + /v1/goods -> /api/v1/goods）
+func methodPath(data ServiceInfo, method MethodInfo) string {
+	path, _ := stripPathPrefix(method.HttpPath, data.StripPathPrefix)
+	if data.PathPrefix == "" {
+		return path
 	}
+	return data.PathPrefix + "/" + strings.TrimLeft(path, "/")
+}
 
-	// 获取 HTTP 注解
-	httpRuleExt := proto.GetExtension(options, annotations.E_Http)
-	if httpRuleExt == nil {
-		return nil
+// stripPathPrefix 去掉路径开头的前缀，只在完整的路径段处匹配（/internal 不会去掉 /internals/x 的开头）
+// 去掉后为空时返回 /；不以前缀开头时原样返回，ok 为 false
+func stripPathPrefix(path, prefix string) (string, bool) {
+	if prefix == "" {
+		return path, true
 	}
-
-	rule, ok := httpRuleExt.(*annotations.HttpRule)
-	if !ok || rule == nil {
-		return nil
+	rest := strings.TrimPrefix(path, prefix)
+	if rest == path || (rest != "" && rest[0] != '/') {
+		return path, false
+	}
+	if rest == "" {
+		return "/", true
 	}
+	return rest, true
+}
 
-	// 使用反射安全地访问 Pattern 字段
-	ruleValue := reflect.ValueOf(rule).Elem()
-	patternField := ruleValue.FieldByName("Pattern")
-	if !patternField.IsValid() || patternField.IsNil() {
-		return nil
+// renderPath 将 HTTP 路径渲染为 JS/TS 字符串表达式
+// 无变量时为单引号字符串；有变量时为模板字符串，变量从 param 对象中取值（keys 为字段路径到访问路径的映射）：
+// 单段变量（{id}、{id=*}）在 encode 为 true 时使用 encodeURIComponent 编码；多段变量（{path=**}、{name=shelves/*}）原样转发，保留其中的 /
+func renderPath(path, param string, keys map[string]string, encode bool) string {
+	literals, vars := parsePathTemplate(path)
+	if len(vars) == 0 {
+		return "'" + path + "'"
 	}
+	var b strings.Builder
+	b.WriteString("`")
+	b.WriteString(literals[0])
+	for i, v := range vars {
+		key, ok := keys[v.FieldPath]
+		if !ok {
+			key = fieldKeyPath(nil, v.FieldPath, false)
+		}
+		expr := param + "." + key
+		if encode && (v.Pattern == "" || v.Pattern == "*") {
+			expr = "encodeURIComponent(" + expr + ")"
+		}
+		b.WriteString("${")
+		b.WriteString(expr)
+		b.WriteString("}")
+		b.WriteString(literals[i+1])
+	}
+	b.WriteString("`")
+	return b.String()
+}
 
-	// 优先使用 post/get/put/delete/patch 中的路径
-	// 使用类型断言访问不同的 HTTP 方法
-	patternInterface := patternField.Interface()
+// fieldKey 返回字段在生成代码中的键名：默认为 proto 字段名的 camelCase（与 ts-proto 默认一致），
+// useJSONName 为 true 时使用 proto 中声明的 json_name
+func fieldKey(field *protogen.Field, useJSONName bool) string {
+	if useJSONName {
+		return field.Desc.JSONName()
+	}
+	return snakeToCamel(string(field.Desc.Name()))
+}
 
-	switch v := patternInterface.(type) {
-	case *annotations.HttpRule_Post:
-		if len(v.Post) > 0 {
-			return &HttpRule{
-				Method: "post",
-				Path:   v.Post,
+// fieldKeyPath 将以 proto 字段名表示的字段路径转为生成代码中的访问路径（例如：book.book_id -> book.bookId）
+// 沿 msg 逐级查找字段，找不到时按 camelCase 转换
+func fieldKeyPath(msg *protogen.Message, fieldPath string, useJSONName bool) string {
+	parts := strings.Split(fieldPath, ".")
+	for i, name := range parts {
+		var found *protogen.Field
+		if msg != nil {
+			for _, field := range msg.Fields {
+				if string(field.Desc.Name()) == name {
+					found = field
+					break
+				}
 			}
 		}
-	case *annotations.HttpRule_Get:
-		if len(v.Get) > 0 {
-			return &HttpRule{
-				Method: "get",
-				Path:   v.Get,
-			}
+		if found == nil {
+			parts[i] = snakeToCamel(name)
+			msg = nil
+			continue
 		}
-	case *annotations.HttpRule_Put:
-		if len(v.Put) > 0 {
-			return &HttpRule{
-				Method: "put",
-				Path:   v.Put,
-			}
+		parts[i] = fieldKey(found, useJSONName)
+		msg = found.Message
+	}
+	return strings.Join(parts, ".")
+}
+
+// classifyFields 按路径模板将请求消息的顶层字段分为路径参数和查询参数
+// 返回的字段名均为生成代码中的键名（见 fieldKey），pathKeys 为路径变量字段路径到访问路径的映射
+func classifyFields(input *protogen.Message, path string, useJSONName bool) (pathParams, queryFields []string, pathKeys map[string]string) {
+	bound := make(map[string]bool)
+	pathKeys = make(map[string]string)
+	_, vars := parsePathTemplate(path)
+	for _, v := range vars {
+		pathKeys[v.FieldPath] = fieldKeyPath(input, v.FieldPath, useJSONName)
+		// 嵌套字段（如 book.id）按顶层字段 book 归类
+		top := strings.SplitN(v.FieldPath, ".", 2)[0]
+		if !bound[top] {
+			bound[top] = true
+			pathParams = append(pathParams, fieldKeyPath(input, top, useJSONName))
 		}
-	case *annotations.HttpRule_Delete:
-		if len(v.Delete) > 0 {
-			return &HttpRule{
-				Method: "delete",
-				Path:   v.Delete,
-			}
+	}
+	if input == nil {
+		return pathParams, nil, pathKeys
+	}
+	for _, field := range input.Fields {
+		if !bound[string(field.Desc.Name())] {
+			queryFields = append(queryFields, fieldKey(field, useJSONName))
 		}
-	case *annotations.HttpRule_Patch:
-		if len(v.Patch) > 0 {
-			return &HttpRule{
-				Method: "patch",
-				Path:   v.Patch,
-			}
+	}
+	return pathParams, queryFields, pathKeys
+}
+
+// isPaginated 判断是否为分页方法：请求含 page_token 字段，响应含 next_page_token 字段（AIP-158 约定）
+func isPaginated(input, output *protogen.Message) bool {
+	return hasField(input, "page_token") && hasField(output, "next_page_token")
+}
+
+// hasField 判断消息是否包含指定名称（proto 字段名）的字段
+func hasField(msg *protogen.Message, name string) bool {
+	if msg == nil {
+		return false
+	}
+	for _, field := range msg.Fields {
+		if string(field.Desc.Name()) == name {
+			return true
 		}
 	}
+	return false
+}
 
-	return nil
+// quoteKeys 将字段名列表渲染为 TS 字符串字面量联合类型（例如：'a' | 'b'），为空时返回 never
+func quoteKeys(keys []string) string {
+	if len(keys) == 0 {
+		return "never"
+	}
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		quoted[i] = "'" + k + "'"
+	}
+	return strings.Join(quoted, " | ")
 }
 
-// HttpRule HTTP 规则结构
-type HttpRule struct {
-	Method string
-	Path   string
+// toCamelCaseWithPolicy 按 first_acronym 策略将名称转为小写开头
+// lower: HTTPService -> httpService（开头的缩写词整体小写，即 toCamelCase）
+// first: HTTPService -> hTTPService（只小写首字母）
+// preserve: HTTPService -> HTTPService（开头为缩写词时保持不变，普通单词仍小写首字母）
+func toCamelCaseWithPolicy(s, policy string) string {
+	switch policy {
+	case "first":
+		return lowerFirst(s)
+	case "preserve":
+		if leadingAcronym(s) >= 2 {
+			return s
+		}
+	}
+	return toCamelCase(s)
 }
 
-// toCamelCase 将首字母转为小写（例如：Goods -> goods）
-func toCamelCase(s string) string {
+// toPascalCase 将首字母转为大写（例如：goodsApi -> GoodsApi）
+func toPascalCase(s string) string {
 	if len(s) == 0 {
 		return s
 	}
-	return strings.ToLower(s[:1]) + s[1:]
+	return strings.ToUpper(s[:1]) + s[1:]
 }
 
 // uniqueAndSort 去重并排序字符串切片
@@ -394,11 +1772,13 @@ func uniqueAndSort(strs []string) []string {
 // 只收集请求和响应类型本身，不递归收集嵌套类型（因为 TypeScript 类型系统会自动处理）
 // 返回 map[importPath][]sortedTypeNames，避免重复分组
 // methods 参数用于匹配哪些方法需要处理（避免重复调用 extractHttpRule）
-func collectTypeImports(gen *protogen.Plugin, service *protogen.Service, methods []MethodInfo) map[string][]string {
+// verbResponses 中响应处理为 void 的方法不导入响应类型（生成代码中不会用到）
+// 没有请求参数的方法不导入请求类型，protocol=connect 时客户端类型仍会用到，照常导入
+func collectTypeImports(gen *protogen.Plugin, service *protogen.Service, methods []MethodInfo, verbResponses map[string]string, protocol string) map[string][]string {
 	// 创建方法名到 MethodInfo 的映射，用于快速查找
-	methodMap := make(map[string]bool)
+	methodMap := make(map[string]MethodInfo)
 	for _, m := range methods {
-		methodMap[m.MethodName] = true
+		methodMap[m.MethodName] = m
 	}
 
 	typeFileMap := make(map[string]string) // typeName -> protoFilePath
@@ -406,12 +1786,13 @@ func collectTypeImports(gen *protogen.Plugin, service *protogen.Service, methods
 	// 从实际的 method 对象中收集请求和响应类型（使用已提取的 methods 避免重复调用 extractHttpRule）
 	for _, method := range service.Methods {
 		// 只处理在 methods 列表中的方法（这些已经通过 extractHttpRule 验证）
-		if !methodMap[string(method.Desc.Name())] {
+		methodInfo, ok := methodMap[string(method.Desc.Name())]
+		if !ok {
 			continue
 		}
 
-		// 收集请求类型
-		if method.Input != nil {
+		// 收集请求类型（Struct / Value / ListValue 直接使用 JSON 类型，不需要导入）
+		if method.Input != nil && !isJSONWellKnown(method.Input) && (!methodInfo.NoRequest || protocol == "connect") {
 			typeName := string(method.Input.Desc.Name())
 			// 使用 Desc.ParentFile() 直接获取文件，O(1) 复杂度
 			if fileDesc := method.Input.Desc.ParentFile(); fileDesc != nil {
@@ -419,8 +1800,12 @@ func collectTypeImports(gen *protogen.Plugin, service *protogen.Service, methods
 			}
 		}
 
-		// 收集响应类型
-		if method.Output != nil {
+		// 收集响应类型（typed_pages 的分页响应改为收集列表元素类型）
+		if methodInfo.PageItem != nil && verbResponses[methodInfo.HttpMethod] != "void" {
+			if fileDesc := methodInfo.PageItem.Desc.ParentFile(); fileDesc != nil {
+				typeFileMap[methodInfo.PageItemType] = fileDesc.Path()
+			}
+		} else if method.Output != nil && !isJSONWellKnown(method.Output) && verbResponses[methodInfo.HttpMethod] != "void" {
 			typeName := string(method.Output.Desc.Name())
 			// 使用 Desc.ParentFile() 直接获取文件，O(1) 复杂度
 			if fileDesc := method.Output.Desc.ParentFile(); fileDesc != nil {
@@ -468,101 +1853,636 @@ func protoFileToImportPath(protoFilePath string) string {
 	return path
 }
 
+// headerVersion 返回需要写入文件头部的插件版本，未开启 version_in_header 时返回空
+func headerVersion(config *PluginConfig) string {
+	if !config.VersionInHeader {
+		return ""
+	}
+	return pluginVersion()
+}
+
+// headerBanner 返回文件头部 DO NOT EDIT 注释中的工具名，关闭 banner 时返回空
+func headerBanner(config *PluginConfig) string {
+	if !config.Banner {
+		return ""
+	}
+	return config.BannerTool
+}
+
+// aggregateHeader 返回汇总文件（flatten、index 等）使用的头部数据：汇总了多个服务，不写来源 proto 文件
+func aggregateHeader(data ServiceInfo) ServiceInfo {
+	data.SourceFile = ""
+	return data
+}
+
+// writeHeader 写入生成文件头部注释（TS、JS 共用）
+// 开启 banner 时写入 Code generated by xxx. DO NOT EDIT. 及来源 proto 文件，插件版本写在工具名之后；
+// 配置 lint_ignore 时在其后写入忽略指令
+func writeHeader(buf *bytes.Buffer, data ServiceInfo) {
+	start := buf.Len()
+	if data.Banner != "" {
+		buf.WriteString("// Code generated by ")
+		buf.WriteString(data.Banner)
+		if data.PluginVersion != "" {
+			buf.WriteString(" ")
+			buf.WriteString(data.PluginVersion)
+		}
+		buf.WriteString(". DO NOT EDIT.\n")
+		if data.SourceFile != "" {
+			buf.WriteString("// source: ")
+			buf.WriteString(data.SourceFile)
+			buf.WriteString("\n")
+		}
+	} else if data.PluginVersion != "" {
+		buf.WriteString("// Generated by protoc-gen-frontend-api ")
+		buf.WriteString(data.PluginVersion)
+		buf.WriteString("\n")
+	}
+	writeLintIgnore(buf, data.LintIgnore)
+	if buf.Len() > start {
+		buf.WriteString("\n")
+	}
+}
+
+// writeTypeImports 写入 ts-proto 类型的 import type 语句，按 importPath 排序以保证生成稳定
+func writeTypeImports(buf *bytes.Buffer, typesImportPath string, typeImports map[string][]string) {
+	writeTypeStatements(buf, "import", typesImportPath, typeImports)
+}
+
+// writeTypeStatements 按 importPath 排序写入 keyword type { ... } from '...' 语句，keyword 为 import 或 export
+func writeTypeStatements(buf *bytes.Buffer, keyword, typesImportPath string, typeImports map[string][]string) {
+	importPaths := make([]string, 0, len(typeImports))
+	for k := range typeImports {
+		importPaths = append(importPaths, k)
+	}
+	sort.Strings(importPaths)
+	for _, importPath := range importPaths {
+		fullImportPath := typesImportPath
+		if !strings.HasSuffix(fullImportPath, "/") && importPath != "" {
+			fullImportPath += "/"
+		}
+		fullImportPath += importPath
+
+		buf.WriteString(keyword)
+		buf.WriteString(" type { ")
+		buf.WriteString(strings.Join(typeImports[importPath], ", "))
+		buf.WriteString(" } from '")
+		buf.WriteString(fullImportPath)
+		buf.WriteString("';\n")
+	}
+}
+
 // generateTypeScriptCode 生成 TypeScript API 代码内容
 // 最佳实践：引用 ts-proto 生成的类型定义，而不是自己生成
 func generateTypeScriptCode(data ServiceInfo) []byte {
 	var buf bytes.Buffer
+	writeHeader(&buf, data)
+	writeVueHeader(&buf, data)
+	writeServiceDeprecation(&buf, data)
 
 	// 写入 service import
-	serviceImport := data.ServiceImport
-	buf.WriteString("import service from '")
-	buf.WriteString(serviceImport)
-	buf.WriteString("';\n")
+	writeServiceImport(&buf, data, data.ServiceImport)
+	writeReactQueryImport(&buf, data)
+	writeSWRImport(&buf, data)
+	writeVueImport(&buf, data)
+	writeZodImport(&buf, data)
 
-	// 写入类型定义导入（从 ts-proto 生成的文件导入），按 importPath 排序以保证生成稳定
-	if len(data.TypeImports) > 0 {
-		importPaths := make([]string, 0, len(data.TypeImports))
-		for k := range data.TypeImports {
-			importPaths = append(importPaths, k)
-		}
-		sort.Strings(importPaths)
-		for _, importPath := range importPaths {
-			typeNames := data.TypeImports[importPath]
-			fullImportPath := data.TypesImportPath
-			if !strings.HasSuffix(fullImportPath, "/") && importPath != "" {
-				fullImportPath += "/"
-			}
-			fullImportPath += importPath
+	// 写入类型定义导入（从 ts-proto 生成的文件导入）
+	writeTypeImports(&buf, data.TypesImportPath, data.TypeImports)
+
+	buf.WriteString("\n")
 
-			buf.WriteString("import type { ")
-			buf.WriteString(strings.Join(typeNames, ", "))
-			buf.WriteString(" } from '")
-			buf.WriteString(fullImportPath)
-			buf.WriteString("';\n")
+	// 为 GET 方法生成单独的查询参数类型（仅包含未绑定到路径的字段）
+	if data.SplitQueryTypes {
+		wrote := false
+		for _, method := range data.Methods {
+			if !hasQueryType(data, method) {
+				continue
+			}
+			buf.WriteString("export type ")
+			buf.WriteString(method.MethodName)
+			buf.WriteString("Query = Pick<")
+			buf.WriteString(method.RequestType)
+			buf.WriteString(", ")
+			buf.WriteString(quoteKeys(method.QueryFields))
+			buf.WriteString(">;\n")
+			wrote = true
+		}
+		if wrote {
+			buf.WriteString("\n")
 		}
 	}
 
-	buf.WriteString("\n")
+	writePageType(&buf, data)
+	writeStrictTypes(&buf, data.StrictTypes)
+	writeEnumConstants(&buf, data.RequestEnums, true, "  ", data.TrailingComma)
+	writeEnumHelpers(&buf, data.Enums, true)
+	writeEnumLabels(&buf, data.LabelEnums, true, "  ", data.TrailingComma)
+	writeTimeoutConstants(&buf, data)
+	writeConfigure(&buf, data, true, "  ")
+	writeConnectClient(&buf, data, true, "  ")
+	writeFetchHelper(&buf, data, true, "  ")
+	writeRetryHelper(&buf, data, true, "  ")
+	writeCacheHelper(&buf, data, true, "  ")
+	writeAbortAll(&buf, data, true, "  ")
+	writeZodSchemas(&buf, data, "  ")
+	writeDefaults(&buf, data, "  ")
+	writeBodyKeys(&buf, data, true, "  ")
 
 	// 生成 API 对象
-	buf.WriteString("export const ")
+	writeApiObject(&buf, data, true, "  ")
+	writeRequestTypeNames(&buf, data, true)
+	writePaths(&buf, data, true, "  ")
+	writeResultUnion(&buf, data)
+	writeOpsMap(&buf, data)
+	writeExamples(&buf, data, "  ")
+
+	// 全局 ApiRegistry 增强：各服务文件中的声明会合并为一个接口
+	if data.EmitRegistryAugmentation {
+		buf.WriteString("declare global {\n")
+		buf.WriteString("  interface ApiRegistry {\n")
+		buf.WriteString("    ")
+		buf.WriteString(data.ApiFileName)
+		buf.WriteString(": typeof ")
+		buf.WriteString(data.ApiFileName)
+		buf.WriteString(";\n")
+		buf.WriteString("  }\n")
+		buf.WriteString("}\n\n")
+	}
+
+	writeInfiniteQueryHooks(&buf, data, true, "  ")
+	writeQueryHooks(&buf, data, true, "  ")
+	writeSWRHooks(&buf, data, true, "  ")
+	writeComposables(&buf, data, true, "  ")
+	buf.WriteString("export default ")
 	buf.WriteString(data.ApiFileName)
-	buf.WriteString(" = {\n")
+	buf.WriteString(";\n")
+
+	return buf.Bytes()
+}
+
+// typeScriptMembers 渲染一个方法在 TS API 对象中的成员（方法本身及可选的路径构造函数），name 为成员名
+func typeScriptMembers(data ServiceInfo, method MethodInfo, name string) []string {
+	return typeScriptDecls(data, method, name, "  ", "  ", false)
+}
+
+// typeScriptDecls 渲染一个方法的 TS 声明：named 为 false 时为对象成员（indent 为成员缩进），
+// 为 true 时为顶层的 export const 声明；step 为方法体每层缩进
+func typeScriptDecls(data ServiceInfo, method MethodInfo, name, indent, step string, named bool) []string {
+	var members []string
+	var m strings.Builder
+	m.WriteString(jsDocComment(method.Comment, methodTags(data, method), indent))
+	m.WriteString(declPrefix(name, indent, named))
+	if data.ErrorTuple {
+		m.WriteString("async ")
+	}
+	switch {
+	case method.NoRequest:
+		m.WriteString("()")
+	case data.CompatArgs:
+		m.WriteString("(" + compatParams(data, method, true) + ")")
+	default:
+		m.WriteString("(" + data.ParamName + ": " + requestParamType(data, method) + ")")
+	}
+	m.WriteString(": Promise<")
+	m.WriteString(methodResultType(data, method))
+	m.WriteString("> ")
+	m.WriteString(methodBody(data, method, indent, step, true))
+	members = append(members, m.String())
+
+	// 路径构造函数：只返回插值后的 URL，不发请求
+	if data.EmitPathBuilders {
+		var p strings.Builder
+		p.WriteString(declPrefix(name+"Path", indent, named))
+		p.WriteString("(")
+		if len(method.PathParams) > 0 {
+			p.WriteString(data.ParamName + ": Pick<")
+			p.WriteString(method.RequestType)
+			p.WriteString(", ")
+			p.WriteString(quoteKeys(method.PathParams))
+			p.WriteString(">")
+		}
+		p.WriteString("): string ")
+		p.WriteString(arrowBody(data, renderPath(methodPath(data, method), data.ParamName, method.PathKeys, data.EncodePathParams), indent, indent+step, false))
+		members = append(members, p.String())
+	}
+	return members
+}
+
+// javaScriptMembers 渲染一个方法在 JS API 对象中的成员，name 为成员名
+func javaScriptMembers(data ServiceInfo, method MethodInfo, name string) []string {
+	return javaScriptDecls(data, method, name, "    ", "    ", false)
+}
+
+// javaScriptDecls 渲染一个方法的 JS 声明，参数同 typeScriptDecls
+func javaScriptDecls(data ServiceInfo, method MethodInfo, name, indent, step string, named bool) []string {
+	params := "(" + data.ParamName + ") "
+	switch {
+	case method.NoRequest:
+		params = "() "
+	case data.CompatArgs:
+		params = "(" + compatParams(data, method, false) + ") "
+	}
+	if data.ErrorTuple {
+		params = "async " + params
+	}
+	members := []string{jsDocComment(method.Comment, methodTags(data, method), indent) + declPrefix(name, indent, named) + params + methodBody(data, method, indent, step, false)}
+	if data.EmitPathBuilders {
+		param := ""
+		if len(method.PathParams) > 0 {
+			param = data.ParamName
+		}
+		members = append(members, declPrefix(name+"Path", indent, named)+"("+param+") "+
+			arrowBody(data, renderPath(methodPath(data, method), data.ParamName, method.PathKeys, data.EncodePathParams), indent, indent+step, false))
+	}
+	return members
+}
+
+// methodBody 渲染方法的 => 及函数体：error_tuple 时为 try/catch 块体，否则按 arrow_style 渲染
+// 开启 compat_args 时强制使用块体，并在开头插入参数归一化语句（没有请求参数的方法除外）；
+// merge_defaults 且 body 指定字段时同样使用块体，在开头合并一次默认值；memberIndent 为成员所在缩进，step 为每层缩进
+func methodBody(data ServiceInfo, method MethodInfo, memberIndent, step string, typed bool) string {
+	if method.NoRequest {
+		data.CompatArgs = false
+	}
+	var preamble string
+	if data.CompatArgs {
+		preamble = compatPreamble(data, method, memberIndent+step, typed)
+	}
+	if mergesBody(data, method) {
+		preamble += mergedPreamble(data, method, memberIndent+step)
+	}
+	if data.ErrorTuple {
+		return withCompatArgs(errorTupleBody(data, method, memberIndent, step, typed), preamble)
+	}
+	if preamble != "" {
+		block := data
+		block.ArrowStyle = "block"
+		return withCompatArgs(arrowBody(block, callExpr(data, method, typed), memberIndent, memberIndent+step, false), preamble)
+	}
+	return arrowBody(data, callExpr(data, method, typed), memberIndent, memberIndent+step, typed)
+}
+
+// callExpr 返回方法体中调用 service 的表达式（如 service.get(`/v1/x/${...}`, data)）
+// client=fetch 时改为调用模块内的 request，typed 为 true 时带上响应类型参数；protocol=connect 时调用注入的 client；流式方法为桩方法
+func callExpr(data ServiceInfo, method MethodInfo, typed bool) string {
+	if method.StreamKind != "" {
+		return streamStub(data, method)
+	}
+	if data.Protocol == "connect" {
+		return wrapAbort(data, wrapCache(data, method, wrapRetry(data, connectCall(data, method)+responseTransform(data, method))))
+	}
+	path := withBaseURL(data, renderPath(methodPath(data, method), data.ParamName, method.PathKeys, data.EncodePathParams))
+	if data.Client == "fetch" {
+		return wrapAbort(data, wrapCache(data, method, wrapRetry(data, fetchCall(data, method, path, typed)+responseTransform(data, method))))
+	}
+	// fluent 风格：先以 url(path) 指定路径，再链式调用 HTTP 方法
+	args := requestArgs(data, method)
+	// get_params=query：GET 的数据作为请求配置的 params 传入（如 axios.get(url, { params })），请求选项合并到同一配置对象
+	if data.GetParams == "query" && method.HttpMethod == "get" {
+		options := requestOptions(data)
+		if !method.NoRequest {
+			options = append([]string{"params: " + requestData(data, method)}, options...)
+		}
+		args = ""
+		if len(options) > 0 {
+			args = "{ " + strings.Join(options, ", ") + " }"
+		}
+	}
+	if data.CallStyle == "fluent" {
+		return wrapAbort(data, wrapCache(data, method, wrapRetry(data, "service.url("+path+")."+clientMethod(data, method)+"("+strings.TrimSuffix(clientVerbArg(data, method)+args, ", ")+")"+responseTransform(data, method))))
+	}
+	return wrapAbort(data, wrapCache(data, method, wrapRetry(data, "service."+clientMethod(data, method)+"("+strings.TrimSuffix(clientVerbArg(data, method)+path+", "+args, ", ")+")"+responseTransform(data, method))))
+}
+
+// requestArgs 返回 service 调用中路径之后的参数：请求数据及请求选项（body 指定字段时其余字段作为 params 并入请求选项）；
+// 没有请求参数的方法不传数据，有请求选项时以 undefined 占位，没有时返回空串
+func requestArgs(data ServiceInfo, method MethodInfo) string {
+	if params := bodyParams(data, method); params != "" {
+		return requestData(data, method) + ", { " + strings.Join(append([]string{params}, requestOptions(data)...), ", ") + " }"
+	}
+	if !method.NoRequest {
+		return requestData(data, method) + configOptions(data)
+	}
+	if options := configOptions(data); options != "" {
+		return "undefined" + options
+	}
+	return ""
+}
+
+// requestData 返回发送给 service 的请求数据表达式（方法参数，默认为 data）：开启 merge_defaults 时为 { ...xxxDefaults, ...data }，
+// HTTP 规则的 body 指定字段时只取该字段（如 data.book，merge_defaults 时为 merged.book），开启 body_key_case 时再以 renameKeys 改写顶层键名
+func requestData(data ServiceInfo, method MethodInfo) string {
+	if method.BodyField != "" {
+		source := data.ParamName
+		if mergesBody(data, method) {
+			source = mergedName(data)
+		}
+		return renameBodyKeys(data, method, source+"."+method.BodyField)
+	}
+	expr := data.ParamName
+	if data.MergeDefaults {
+		expr = "{ ..." + defaultsName(method) + ", ..." + data.ParamName + " }"
+	}
+	return renameBodyKeys(data, method, expr)
+}
+
+// arrowBody 渲染箭头函数中 => 及其后的函数体
+// arrow_style 为 block 时生成 { return expr; } 块体；否则为表达式体，wrap 为 true 时表达式换行并缩进 bodyIndent
+func arrowBody(data ServiceInfo, expr, memberIndent, bodyIndent string, wrap bool) string {
+	if data.ArrowStyle == "block" {
+		return "=> {\n" + bodyIndent + "return " + expr + ";\n" + memberIndent + "}"
+	}
+	if wrap {
+		return "=>\n" + bodyIndent + expr
+	}
+	return "=> " + expr
+}
+
+// errorTupleBody 渲染 error_tuple 模式的方法体：await 调用并以 [null, res] 返回，异常时返回 [err, null]，不向调用方抛出
+// memberIndent 为成员所在缩进，step 为每层缩进；typed 为 true 时为 catch 到的异常加 as Error 断言
+func errorTupleBody(data ServiceInfo, method MethodInfo, memberIndent, step string, typed bool) string {
+	in1 := memberIndent + step
+	in2 := in1 + step
+	var b strings.Builder
+	b.WriteString("=> {\n")
+	b.WriteString(in1 + "try {\n")
+	if responseType(data, method) == "void" {
+		b.WriteString(in2 + "await " + callExpr(data, method, typed) + ";\n")
+		b.WriteString(in2 + "return [null, null];\n")
+	} else {
+		b.WriteString(in2 + "return [null, await " + callExpr(data, method, typed) + "];\n")
+	}
+	b.WriteString(in1 + "} catch (err) {\n")
+	if typed {
+		b.WriteString(in2 + "return [err as Error, null];\n")
+	} else {
+		b.WriteString(in2 + "return [err, null];\n")
+	}
+	b.WriteString(in1 + "}\n")
+	b.WriteString(memberIndent + "}")
+	return b.String()
+}
+
+// methodResultType 返回 TS 方法 Promise 的完整结果类型：error_tuple 时为 [Error | null, T | null] 元组
+func methodResultType(data ServiceInfo, method MethodInfo) string {
+	t := responseType(data, method)
+	if !data.ErrorTuple {
+		return t
+	}
+	if t == "void" {
+		return "[Error | null, null]"
+	}
+	return "[Error | null, " + t + " | null]"
+}
+
+// clientMethod 返回方法调用 service 时使用的方法名：配置了 method_client 时使用覆盖值，
+// 否则按 client_style 为 HTTP 方法（get）、大写的 HTTP 方法（GET）或统一的 request
+func clientMethod(data ServiceInfo, method MethodInfo) string {
+	if method.ClientMethod != "" {
+		return method.ClientMethod
+	}
+	switch data.ClientStyle {
+	case "verb_upper":
+		return strings.ToUpper(method.HttpMethod)
+	case "unified":
+		return "request"
+	}
+	return method.HttpMethod
+}
 
-	// 写入方法
+// clientVerbArg client_style=unified 时作为 service.request 第一个参数的 HTTP 方法（如 'GET', ），其余情况为空
+func clientVerbArg(data ServiceInfo, method MethodInfo) string {
+	if data.ClientStyle != "unified" || method.ClientMethod != "" {
+		return ""
+	}
+	return "'" + strings.ToUpper(method.HttpMethod) + "', "
+}
+
+// responseTransform 返回按 verb_response 追加在调用后的响应处理
+func responseTransform(data ServiceInfo, method MethodInfo) string {
+	switch data.VerbResponses[method.HttpMethod] {
+	case "void":
+		return ".then(() => undefined)"
+	case "data":
+		return ".then((res) => res.data)"
+	}
+	return ""
+}
+
+// responseType 返回 TS 方法 Promise 的结果类型，verb_response 为 void 时为 void，typed_pages 的分页响应为 Page<T>
+func responseType(data ServiceInfo, method MethodInfo) string {
+	if data.VerbResponses[method.HttpMethod] == "void" {
+		return "void"
+	}
+	if method.PageItemType != "" {
+		return "Page<" + strictResponseType(data, method.PageItemType) + ">"
+	}
+	return strictResponseType(data, method.ResponseType)
+}
+
+// hasQueryType 判断方法是否单独生成 XxxQuery 类型：开启 split_query_types 的 GET 方法，没有请求参数的方法除外
+func hasQueryType(data ServiceInfo, method MethodInfo) bool {
+	return data.SplitQueryTypes && method.HttpMethod == "get" && !method.NoRequest
+}
+
+// requestParamType 返回 TS 方法 data 参数的类型
+// 开启 split_query_types 时，GET 方法使用 XxxQuery，路径参数仍从请求类型中 Pick
+func requestParamType(data ServiceInfo, method MethodInfo) string {
+	if !hasQueryType(data, method) {
+		return method.RequestType
+	}
+	queryType := method.MethodName + "Query"
+	if len(method.PathParams) == 0 {
+		return queryType
+	}
+	return queryType + " & Pick<" + method.RequestType + ", " + quoteKeys(method.PathParams) + ">"
+}
+
+// writeRequestTypeNames 生成方法名到请求消息名的映射常量（如 OrderRequestTypes），便于运行时按操作查找对应的类型/表单定义
+// typed 为 true 时追加 as const，使每个值保持字面量类型
+func writeRequestTypeNames(buf *bytes.Buffer, data ServiceInfo, typed bool) {
+	if !data.EmitRequestTypeNames {
+		return
+	}
+	buf.WriteString("export const ")
+	buf.WriteString(data.ServiceName)
+	buf.WriteString("RequestTypes = {\n")
 	for i, method := range data.Methods {
 		buf.WriteString("  ")
 		buf.WriteString(method.MethodName)
-		buf.WriteString(": (data: ")
-		buf.WriteString(method.RequestType)
-		buf.WriteString("): Promise<")
-		buf.WriteString(method.ResponseType)
-		buf.WriteString("> =>\n")
-		buf.WriteString("    service.")
-		buf.WriteString(method.HttpMethod)
-		buf.WriteString("('")
-		buf.WriteString(method.HttpPath)
-		buf.WriteString("', data)")
-
-		if i < len(data.Methods)-1 {
-			buf.WriteString(",\n")
-		} else {
-			buf.WriteString("\n")
+		buf.WriteString(": '")
+		buf.WriteString(string(method.Input.Desc.Name()))
+		buf.WriteString("'")
+		buf.WriteString(memberEnd(i == len(data.Methods)-1, data.TrailingComma))
+	}
+	buf.WriteString("}")
+	if typed {
+		buf.WriteString(" as const")
+	}
+	buf.WriteString(";\n\n")
+}
+
+// writePaths 生成方法名到路径模板的映射常量（如 OrderPaths），路径变量保留 {order_id} 形式的占位符，
+// 已按 strip_path_prefix / path_prefix 处理，便于测试、手动调用及据此搭建 mock 服务
+// typed 为 true 时追加 as const，indent 为每层缩进
+func writePaths(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if !data.EmitPaths {
+		return
+	}
+	buf.WriteString("export const ")
+	buf.WriteString(data.ServiceName)
+	buf.WriteString("Paths = {\n")
+	for i, method := range data.Methods {
+		buf.WriteString(indent)
+		buf.WriteString(method.MethodName)
+		buf.WriteString(": ")
+		buf.WriteString(singleQuote(methodPath(data, method)))
+		buf.WriteString(memberEnd(i == len(data.Methods)-1, data.TrailingComma))
+	}
+	buf.WriteString("}")
+	if typed {
+		buf.WriteString(" as const")
+	}
+	buf.WriteString(";\n\n")
+}
+
+// mergeTypeImports 合并所有服务的类型导入
+func mergeTypeImports(services []*ServiceInfo) map[string][]string {
+	merged := make(map[string][]string)
+	for _, svc := range services {
+		for importPath, typeNames := range svc.TypeImports {
+			merged[importPath] = uniqueAndSort(append(merged[importPath], typeNames...))
 		}
 	}
+	return merged
+}
 
-	buf.WriteString("};\n\n")
-	buf.WriteString("export default ")
-	buf.WriteString(data.ApiFileName)
-	buf.WriteString(";\n")
+// writeResultUnion 生成服务所有方法响应类型的联合类型（如 type OrderResult = Order | ListOrdersResp），
+// 按方法顺序去重，verb_response 为 void 的方法不计入，便于编写统一的响应处理函数
+func writeResultUnion(buf *bytes.Buffer, data ServiceInfo) {
+	if !data.EmitResultUnion {
+		return
+	}
+	var types []string
+	seen := make(map[string]bool)
+	for _, method := range data.Methods {
+		t := responseType(data, method)
+		if t == "void" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		types = append(types, t)
+	}
+	if len(types) == 0 {
+		return
+	}
+	buf.WriteString("export type ")
+	buf.WriteString(data.ServiceName)
+	buf.WriteString("Result = ")
+	buf.WriteString(strings.Join(types, " | "))
+	buf.WriteString(";\n\n")
+}
+
+// derefServices 返回服务模板数据的值副本，供按值接收的渲染函数使用
+func derefServices(services []*ServiceInfo) []ServiceInfo {
+	values := make([]ServiceInfo, len(services))
+	for i, svc := range services {
+		values[i] = *svc
+	}
+	return values
+}
+
+// generateBundleDts 生成汇总声明文件 api.d.ts：每个服务一个 XxxApi 接口，以及汇总所有服务的 Api 接口
+// 只声明类型，不声明运行时值，供 JS 项目通过 JSDoc（如 @type {import('./api').UserApi}）获得类型提示
+func generateBundleDts(services []*ServiceInfo) []byte {
+	var buf bytes.Buffer
+	writeHeader(&buf, aggregateHeader(*services[0]))
+	writeTypeImports(&buf, services[0].TypesImportPath, mergeTypeImports(services))
+	buf.WriteString("\n")
+	writePageType(&buf, derefServices(services)...)
+	writeStrictTypes(&buf, mergeStrictTypes(services))
+
+	for _, svc := range services {
+		buf.WriteString("export interface ")
+		buf.WriteString(toPascalCase(svc.ApiFileName))
+		buf.WriteString(" {\n")
+		for _, method := range svc.Methods {
+			buf.WriteString("  ")
+			buf.WriteString(method.MethodName)
+			if method.NoRequest {
+				buf.WriteString("(): Promise<")
+			} else {
+				buf.WriteString("(" + svc.ParamName + ": ")
+				buf.WriteString(method.RequestType)
+				buf.WriteString("): Promise<")
+			}
+			buf.WriteString(methodResultType(*svc, method))
+			buf.WriteString(">;\n")
+			if svc.EmitPathBuilders {
+				buf.WriteString("  ")
+				buf.WriteString(method.MethodName)
+				buf.WriteString("Path(")
+				if len(method.PathParams) > 0 {
+					buf.WriteString(svc.ParamName + ": Pick<")
+					buf.WriteString(method.RequestType)
+					buf.WriteString(", ")
+					buf.WriteString(quoteKeys(method.PathParams))
+					buf.WriteString(">")
+				}
+				buf.WriteString("): string;\n")
+			}
+		}
+		buf.WriteString("}\n\n")
+	}
 
+	buf.WriteString("export interface Api {\n")
+	for _, svc := range services {
+		buf.WriteString("  ")
+		buf.WriteString(svc.ApiFileName)
+		buf.WriteString(": ")
+		buf.WriteString(toPascalCase(svc.ApiFileName))
+		buf.WriteString(";\n")
+	}
+	buf.WriteString("}\n")
 	return buf.Bytes()
 }
 
 // generateJavaScriptCode 按 addressApi.js 风格生成 JS：无类型 import，(data) => service.{method}('path', data)
 func generateJavaScriptCode(data ServiceInfo) []byte {
 	var buf bytes.Buffer
-	buf.WriteString("import service from '")
-	buf.WriteString(data.ServiceImport)
-	buf.WriteString("';\n\n")
-	buf.WriteString("export const ")
-	buf.WriteString(data.ApiFileName)
-	buf.WriteString(" = {\n")
-	for i, method := range data.Methods {
-		buf.WriteString("    ")
-		buf.WriteString(method.MethodName)
-		buf.WriteString(": (data) => service.")
-		buf.WriteString(method.HttpMethod)
-		buf.WriteString("('")
-		buf.WriteString(method.HttpPath)
-		buf.WriteString("', data)")
-		if i < len(data.Methods)-1 {
-			buf.WriteString(",\n")
-		} else {
-			buf.WriteString("\n")
-		}
+	writeHeader(&buf, data)
+	writeVueHeader(&buf, data)
+	writeServiceDeprecation(&buf, data)
+	writeServiceImport(&buf, data, data.ServiceImport)
+	writeReactQueryImport(&buf, data)
+	writeSWRImport(&buf, data)
+	writeVueImport(&buf, data)
+	writeZodImport(&buf, data)
+	if buf.Len() > 0 {
+		buf.WriteString("\n")
 	}
-	buf.WriteString("};\n\n")
+	writeEnumConstants(&buf, data.RequestEnums, false, "    ", data.TrailingComma)
+	writeEnumHelpers(&buf, data.Enums, false)
+	writeEnumLabels(&buf, data.LabelEnums, false, "    ", data.TrailingComma)
+	writeTimeoutConstants(&buf, data)
+	writeConfigure(&buf, data, false, "    ")
+	writeConnectClient(&buf, data, false, "    ")
+	writeFetchHelper(&buf, data, false, "    ")
+	writeRetryHelper(&buf, data, false, "    ")
+	writeCacheHelper(&buf, data, false, "    ")
+	writeAbortAll(&buf, data, false, "    ")
+	writeZodSchemas(&buf, data, "    ")
+	writeDefaults(&buf, data, "    ")
+	writeBodyKeys(&buf, data, false, "    ")
+	writeApiObject(&buf, data, false, "    ")
+	writeRequestTypeNames(&buf, data, false)
+	writePaths(&buf, data, false, "    ")
+	writeExamples(&buf, data, "    ")
+	writeInfiniteQueryHooks(&buf, data, false, "    ")
+	writeQueryHooks(&buf, data, false, "    ")
+	writeSWRHooks(&buf, data, false, "    ")
+	writeComposables(&buf, data, false, "    ")
 	buf.WriteString("export default ")
 	buf.WriteString(data.ApiFileName)
 	buf.WriteString(";\n")
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// manifestFileName incremental 模式下记录生成文件内容哈希的清单文件，位于每个输出目录中
+const manifestFileName = ".frontend-api-manifest.json"
+
+// dirManifest 一个输出目录的生成清单
+type dirManifest struct {
+	previous map[string]string // 上次生成的文件 -> 内容哈希
+	current  map[string]string // 本次生成的文件 -> 内容哈希
+}
+
+// manifestData 清单文件的 JSON 结构
+type manifestData struct {
+	Files map[string]string `json:"files"` // 相对输出目录的文件路径 -> 内容的 sha256
+}
+
+// incrementalStats 本次增量生成的统计
+type incrementalStats struct {
+	written, skipped, removed int
+}
+
+// contentHash 返回文件内容的 sha256 十六进制串
+func contentHash(code []byte) string {
+	sum := sha256.Sum256(code)
+	return hex.EncodeToString(sum[:])
+}
+
+// manifestFor 返回输出目录的清单，首次访问时读取上次的清单文件（不存在或无法解析时视为空）
+func (w *outputWriter) manifestFor(dir string) *dirManifest {
+	if m, ok := w.manifests[dir]; ok {
+		return m
+	}
+	m := &dirManifest{previous: map[string]string{}, current: map[string]string{}}
+	if data, err := os.ReadFile(filepath.Join(dir, manifestFileName)); err == nil {
+		var parsed manifestData
+		if json.Unmarshal(data, &parsed) == nil && parsed.Files != nil {
+			m.previous = parsed.Files
+		}
+	}
+	w.manifests[dir] = m
+	return m
+}
+
+// loadManifests 生成前读取各输出目录上次的清单，使本次没有写入任何文件的目录（如服务被删除或过滤掉）也能在 flush 时删除过期文件
+// 不存在的输出目录跳过（write 同样不写入）
+func (w *outputWriter) loadManifests(dirs []string) {
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			w.manifestFor(dir)
+		}
+	}
+}
+
+// unchanged 记录本次生成的文件哈希，并判断文件内容与上次生成相同且磁盘上的文件仍存在（可跳过写入）
+func (w *outputWriter) unchanged(dir, name string, code []byte) bool {
+	m := w.manifestFor(dir)
+	hash := contentHash(code)
+	m.current[name] = hash
+	if m.previous[name] != hash {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+	return err == nil
+}
+
+// manifestPath 返回清单中记录的文件在输出目录下的路径；清单可能过期或被手动修改，
+// 绝对路径及清理后位于输出目录之外的路径（如 ../x）返回错误，避免删除输出目录以外的文件
+func manifestPath(dir, name string) (string, error) {
+	rel := filepath.FromSlash(name)
+	if name == "" || filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("清单 %s 中的文件路径不合法: %q", filepath.Join(dir, manifestFileName), name)
+	}
+	clean := filepath.Clean(rel)
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("清单 %s 中的文件路径位于输出目录之外: %q", filepath.Join(dir, manifestFileName), name)
+	}
+	return filepath.Join(dir, clean), nil
+}
+
+// flushManifests 删除上次生成而本次不再生成的文件，写入新的清单，并输出写入/跳过/删除的文件数
+func (w *outputWriter) flushManifests() error {
+	dirs := make([]string, 0, len(w.manifests))
+	for dir := range w.manifests {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		m := w.manifests[dir]
+		for name := range m.previous {
+			if _, ok := m.current[name]; ok {
+				continue
+			}
+			path, err := manifestPath(dir, name)
+			if err != nil {
+				return err
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("删除过期文件失败 %s: %v", name, err)
+			}
+			w.stats.removed++
+		}
+		data, err := json.MarshalIndent(manifestData{Files: m.current}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, manifestFileName), append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("写入清单失败 %s: %v", dir, err)
+		}
+	}
+	logf("增量生成: 写入 %d 个文件，跳过 %d 个未变化的文件，删除 %d 个过期文件", w.stats.written, w.stats.skipped, w.stats.removed)
+	return nil
+}
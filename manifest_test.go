@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestPath(t *testing.T) {
+	dir := filepath.Join("out", "api")
+	tests := []struct {
+		name string
+		want string // 为空表示应返回错误
+	}{
+		{"orderApi.ts", filepath.Join(dir, "orderApi.ts")},
+		{"shop/v1/orderApi.ts", filepath.Join(dir, "shop", "v1", "orderApi.ts")},
+		{"shop/../orderApi.ts", filepath.Join(dir, "orderApi.ts")},
+		{"../orderApi.ts", ""},
+		{"shop/../../orderApi.ts", ""},
+		{"..", ""},
+		{".", ""},
+		{"", ""},
+		{"/etc/passwd", ""},
+	}
+	for _, tt := range tests {
+		got, err := manifestPath(dir, tt.name)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("manifestPath(%q) = %q, want error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("manifestPath(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestIncrementalRemovesFilesFromEmptiedDir(t *testing.T) {
+	tsDir, jsDir := t.TempDir(), t.TempDir()
+	file := orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")
+	param := "output_paths=" + tsDir + ",output_paths_js=" + jsDir + ",incremental=true"
+	if _, err := execPlugin(param, file); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(tsDir, "orderApi.ts"), filepath.Join(jsDir, "orderApi.js")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("首次生成后应存在 %s: %v", path, err)
+		}
+	}
+
+	// 服务被过滤掉后两个目录都不再写入任何文件，上次生成的文件仍应删除，清单清空
+	if _, err := execPlugin(param+",exclude_services=OrderService", file); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{tsDir, jsDir} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Name() != manifestFileName {
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			t.Errorf("%s 应只剩清单文件，实际为 %v", dir, names)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, manifestFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); got != "{\n  \"files\": {}\n}\n" {
+			t.Errorf("%s 的清单应为空: %s", dir, got)
+		}
+	}
+}
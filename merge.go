@@ -0,0 +1,57 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// packageGroup merge_by_package 时同一 proto package 下的服务
+type packageGroup struct {
+	FileName string         // 合并文件名（不含扩展名），同时作为导出对象名
+	Services []*ServiceInfo // 组内服务，按生成顺序
+}
+
+// packageFileName 返回 proto package 对应的合并文件名（例如：shop.v1 -> shopV1Api），未声明 package 时使用 flatApi
+func packageFileName(pkg string) string {
+	if pkg == "" {
+		return flatFileName
+	}
+	return snakeToCamel(strings.ReplaceAll(pkg, ".", "_")) + "Api"
+}
+
+// groupByPackage 按 proto package 分组服务，按文件名排序
+func groupByPackage(services []*ServiceInfo) []packageGroup {
+	index := make(map[string]int)
+	var groups []packageGroup
+	for _, svc := range services {
+		name := packageFileName(svc.Package)
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, packageGroup{FileName: name})
+		}
+		groups[i].Services = append(groups[i].Services, svc)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].FileName < groups[j].FileName })
+	return groups
+}
+
+// mergeProblems 检查每个合并对象中是否有重名的键
+func mergeProblems(groups []packageGroup) []string {
+	var problems []string
+	for _, group := range groups {
+		problems = append(problems, flatProblems(group.Services)...)
+	}
+	return problems
+}
+
+// writeMergedFiles 在每个输出目录按 proto package 写入合并文件，每个文件导出与文件名同名的扁平对象
+func writeMergedFiles(groups []packageGroup, config *PluginConfig, out *outputWriter) error {
+	for _, group := range groups {
+		if err := writeFlatFiles(group.Services, config, out, group.FileName, group.FileName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reportFile 返回 shop.v1 包下的 ReportService，GetAdminOrder 返回 admin.v1.Order（与 shop.v1.Order 同名）
+func reportFile() *descriptorpb.FileDescriptorProto {
+	file := protoFile("shop/v1/report.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{protoMessage("ReportReq", protoField("order_id", 1, typeString, ""))},
+		protoService("ReportService",
+			protoMethod("GetAdminOrder", ".shop.v1.ReportReq", ".admin.v1.Order", httpGet("/v1/reports/{order_id}")),
+		),
+	)
+	file.Dependency = append(file.Dependency, "admin/v1/order.proto")
+	return file
+}
+
+func TestMergeByPackage(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,merge_by_package=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		orderFile("admin/v1/order.proto", "admin.v1", "AdminOrderService", "admin"))
+	shop := generatedFile(t, generated, "ts/shopV1Api.ts")
+	assertContains(t, shop,
+		"from '@/api/proto-types/shop/v1/order';",
+		"export const shopV1Api = {",
+		"orderGetOrder: (data: GetOrderReq): Promise<Order> =>",
+	)
+	assertNotContains(t, shop, "admin/v1/order")
+	admin := generatedFile(t, generated, "ts/adminV1Api.ts")
+	assertContains(t, admin, "from '@/api/proto-types/admin/v1/order';", "adminOrderGetOrder:")
+}
+
+func TestMergeByPackageRejectsDuplicateTypeNames(t *testing.T) {
+	_, err := runPlugin("output_paths=ts,merge_by_package=true",
+		orderFile("admin/v1/order.proto", "admin.v1", "AdminOrderService", "admin"),
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		reportFile())
+	assertErrorContains(t, err, "shopV1Api.ts 中的类型 Order 同时来自 @/api/proto-types/shop/v1/order 与 @/api/proto-types/admin/v1/order")
+}
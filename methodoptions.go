@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// 自定义方法选项的字段编号，定义见 proto/frontend/options.proto
+const (
+	frontendIgnoreField    = 50901 // (frontend.ignore)
+	frontendTimeoutMsField = 50902 // (frontend.timeout_ms)
+)
+
+// methodOptionVarint 读取方法选项中字段编号为 num 的 varint 自定义选项（重复出现时以最后一次为准）
+// 插件未注册这些扩展，选项保留在 MethodOptions 的未知字段中，直接按字段编号解析
+func methodOptionVarint(method *protogen.Method, num protowire.Number) (uint64, bool) {
+	options, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	if !ok || options == nil {
+		return 0, false
+	}
+	var value uint64
+	found := false
+	b := options.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		n, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return 0, false
+		}
+		b = b[l:]
+		if n == num && typ == protowire.VarintType {
+			v, l := protowire.ConsumeVarint(b)
+			if l < 0 {
+				return 0, false
+			}
+			value, found = v, true
+			b = b[l:]
+			continue
+		}
+		l = protowire.ConsumeFieldValue(n, typ, b)
+		if l < 0 {
+			return 0, false
+		}
+		b = b[l:]
+	}
+	return value, found
+}
+
+// isIgnoredMethod 判断方法是否设置了 option (frontend.ignore) = true
+func isIgnoredMethod(method *protogen.Method) bool {
+	v, ok := methodOptionVarint(method, frontendIgnoreField)
+	return ok && v != 0
+}
+
+// methodTimeout 返回方法通过 option (frontend.timeout_ms) 设置的超时毫秒数，未设置时为 0
+func methodTimeout(method *protogen.Method) int {
+	v, _ := methodOptionVarint(method, frontendTimeoutMsField)
+	return int(int32(v))
+}
+
+// constantCase 将 PascalCase / camelCase 名称转为常量风格（例如：CreateOrder -> CREATE_ORDER，GetHTTPConfig -> GET_HTTP_CONFIG）
+func constantCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// timeoutConstName 返回方法超时常量名（例如：Goods + CreateOrder -> GOODS_CREATE_ORDER_TIMEOUT）
+func timeoutConstName(data ServiceInfo, method MethodInfo) string {
+	return constantCase(data.ServiceName) + "_" + constantCase(method.MethodName) + "_TIMEOUT"
+}
+
+// writeTimeoutConstants 开启 emit_timeout_constants 时为设置了 (frontend.timeout_ms) 的方法导出超时常量
+func writeTimeoutConstants(buf *bytes.Buffer, data ServiceInfo) {
+	if !data.EmitTimeoutConstants {
+		return
+	}
+	wrote := false
+	for _, method := range data.Methods {
+		if method.Timeout <= 0 {
+			continue
+		}
+		buf.WriteString("export const ")
+		buf.WriteString(timeoutConstName(data, method))
+		buf.WriteString(" = ")
+		buf.WriteString(strconv.Itoa(method.Timeout))
+		buf.WriteString(";\n")
+		wrote = true
+	}
+	if wrote {
+		buf.WriteString("\n")
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestToCamelCase(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		// 单个字母
+		{"", ""},
+		{"A", "a"},
+		{"a", "a"},
+		{"AService", "aService"},
+		// 全大写
+		{"SMS", "sms"},
+		{"HTTP", "http"},
+		{"ID", "id"},
+		// 开头的缩写词后接单词
+		{"HTTPService", "httpService"},
+		{"SMSService", "smsService"},
+		{"IDCard", "idCard"},
+		// 普通大小写混合
+		{"Goods", "goods"},
+		{"GoodsService", "goodsService"},
+		{"goodsService", "goodsService"},
+		{"OrderHTTPService", "orderHTTPService"},
+		{"V2Order", "v2Order"},
+	}
+	for _, tt := range tests {
+		if got := toCamelCase(tt.in); got != tt.want {
+			t.Errorf("toCamelCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAcronymServiceFileName(t *testing.T) {
+	file := protoFile("sms/v1/sms.proto", "sms.v1",
+		[]*descriptorpb.DescriptorProto{protoMessage("SendReq", protoField("phone", 1, typeString, ""))},
+		protoService("SMSService",
+			protoMethod("Send", ".sms.v1.SendReq", ".google.protobuf.Empty", httpPost("/v1/sms", "*")),
+		),
+	)
+	generated := mustRunPlugin(t, "output_paths=ts", file)
+	assertContains(t, generatedFile(t, generated, "ts/smsApi.ts"), "export const smsApi = {")
+}
+
+func TestToCamelCaseWithPolicy(t *testing.T) {
+	tests := []struct {
+		in, policy, want string
+	}{
+		{"HTTPService", "lower", "httpService"},
+		{"HTTPService", "first", "hTTPService"},
+		{"HTTPService", "preserve", "HTTPService"},
+		{"SMS", "lower", "sms"},
+		{"SMS", "first", "sMS"},
+		{"SMS", "preserve", "SMS"},
+		// 开头不是缩写词时三种策略相同
+		{"GoodsService", "lower", "goodsService"},
+		{"GoodsService", "first", "goodsService"},
+		{"GoodsService", "preserve", "goodsService"},
+		{"A", "preserve", "a"},
+	}
+	for _, tt := range tests {
+		if got := toCamelCaseWithPolicy(tt.in, tt.policy); got != tt.want {
+			t.Errorf("toCamelCaseWithPolicy(%q, %q) = %q, want %q", tt.in, tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestFirstAcronymFileNames(t *testing.T) {
+	file := protoFile("net/v1/http.proto", "net.v1",
+		[]*descriptorpb.DescriptorProto{protoMessage("ProbeReq", protoField("url", 1, typeString, ""))},
+		protoService("HTTPService",
+			protoMethod("Probe", ".net.v1.ProbeReq", ".google.protobuf.Empty", httpPost("/v1/probe", "*")),
+		),
+	)
+	for policy, want := range map[string]string{
+		"":         "httpApi",
+		"lower":    "httpApi",
+		"first":    "hTTPApi",
+		"preserve": "HTTPApi",
+	} {
+		param := "output_paths=ts"
+		if policy != "" {
+			param += ",first_acronym=" + policy
+		}
+		generated := mustRunPlugin(t, param, file)
+		assertContains(t, generatedFile(t, generated, "ts/"+want+".ts"), "export const "+want+" = {")
+	}
+
+	_, err := runPlugin("output_paths=ts,first_acronym=upper", file)
+	assertErrorContains(t, err, "first_acronym 只支持 lower、first、preserve: upper")
+}
@@ -0,0 +1,38 @@
+package main
+
+import "bytes"
+
+// opsTypeName 返回操作映射类型名（例如：Order -> OrderOps）
+func opsTypeName(data ServiceInfo) string {
+	return data.ServiceName + "Ops"
+}
+
+// writeOpsMap 开启 emit_ops_map 时生成操作名到请求/响应类型的映射接口 XxxOps，
+// 以及按操作名调用 API 对象的泛型入口 call(op, req)，便于统一的调用封装（如带埋点、权限的 dispatcher）获得类型推断；
+// 没有请求参数的方法 req 为 void，调用时可省略
+func writeOpsMap(buf *bytes.Buffer, data ServiceInfo) {
+	if !data.EmitOpsMap {
+		return
+	}
+	ops := opsTypeName(data)
+	buf.WriteString("export interface ")
+	buf.WriteString(ops)
+	buf.WriteString(" {\n")
+	for _, method := range data.Methods {
+		buf.WriteString("  ")
+		buf.WriteString(method.MethodName)
+		buf.WriteString(": { req: ")
+		if method.NoRequest {
+			buf.WriteString("void")
+		} else {
+			buf.WriteString(requestParamType(data, method))
+		}
+		buf.WriteString("; res: ")
+		buf.WriteString(methodResultType(data, method))
+		buf.WriteString(" };\n")
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("export const call = <K extends keyof " + ops + ">(op: K, req: " + ops + "[K]['req']): Promise<" + ops + "[K]['res']> =>\n")
+	buf.WriteString("  (" + data.ApiFileName + "[op] as (data: " + ops + "[K]['req']) => Promise<" + ops + "[K]['res']>)(req);\n\n")
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOutputDirPrecedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		param  string
+		wantTS []OutputPathConfig
+		wantJS []OutputPathConfig
+	}{
+		{"都未设置", "", []OutputPathConfig{}, []OutputPathConfig{}},
+		// 默认 lang=js，output_dir 对应 output_paths_js
+		{"只设置 output_dir", "output_dir=out", []OutputPathConfig{}, []OutputPathConfig{{Path: "out"}}},
+		{"只设置 output_paths_js", "output_paths_js=js", []OutputPathConfig{}, []OutputPathConfig{{Path: "js"}}},
+		{"同时设置时以 output_paths_js 为准", "output_dir=out,output_paths_js=js", []OutputPathConfig{}, []OutputPathConfig{{Path: "js"}}},
+		{"output_paths 不影响 output_dir", "output_dir=out,output_paths=src/api", []OutputPathConfig{{Path: "src/api"}}, []OutputPathConfig{{Path: "out"}}},
+		// lang=ts 时 output_dir 对应 output_paths
+		{"lang=ts 只设置 output_dir", "lang=ts,output_dir=out", []OutputPathConfig{{Path: "out"}}, []OutputPathConfig{}},
+		{"lang=ts 只设置 output_paths", "lang=ts,output_paths=src/api", []OutputPathConfig{{Path: "src/api"}}, []OutputPathConfig{}},
+		{"lang=ts 同时设置时以 output_paths 为准", "lang=ts,output_dir=out,output_paths=src/api", []OutputPathConfig{{Path: "src/api"}}, []OutputPathConfig{}},
+	}
+	for _, tt := range tests {
+		config, err := parsePluginOptions(tt.param)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(config.OutputPaths, tt.wantTS) {
+			t.Errorf("%s: OutputPaths = %+v, want %+v", tt.name, config.OutputPaths, tt.wantTS)
+		}
+		if !reflect.DeepEqual(config.OutputPathsJS, tt.wantJS) {
+			t.Errorf("%s: OutputPathsJS = %+v, want %+v", tt.name, config.OutputPathsJS, tt.wantJS)
+		}
+	}
+}
+
+func TestOutputDirGeneratesFiles(t *testing.T) {
+	file := orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")
+	generated := mustRunPlugin(t, "output_dir=out", file)
+	generatedFile(t, generated, "out/orderApi.js")
+
+	generated = mustRunPlugin(t, "output_dir=out,lang=ts", file)
+	generatedFile(t, generated, "out/orderApi.ts")
+
+	generated = mustRunPlugin(t, "output_dir=out,output_paths_js=js", file)
+	generatedFile(t, generated, "js/orderApi.js")
+	if _, ok := generated["out/orderApi.js"]; ok {
+		t.Error("同时设置 output_paths_js 时不应写入 output_dir")
+	}
+}
+
+func TestParsePluginOptionsMalformedPairs(t *testing.T) {
+	defaults, err := parsePluginOptions("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 与未传参数等价：空白参数、只有逗号、key=（空值保留默认值）
+	for _, param := range []string{"   ", ",", ",,", " , ", "service_import=", "service_import= ,lang="} {
+		config, err := parsePluginOptions(param)
+		if err != nil {
+			t.Errorf("parsePluginOptions(%q): %v", param, err)
+			continue
+		}
+		if !reflect.DeepEqual(config, defaults) {
+			t.Errorf("parsePluginOptions(%q) 应与未传参数相同: %+v", param, config)
+		}
+	}
+
+	// 首尾及连续的逗号忽略，键与值两侧的空白去掉
+	config, err := parsePluginOptions(",service_import = @/request ,,lang=js,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ServiceImport != "@/request" || config.Lang != "js" {
+		t.Errorf("ServiceImport = %q, Lang = %q", config.ServiceImport, config.Lang)
+	}
+
+	// 值中的 = 原样保留
+	config, err = parsePluginOptions("fetch_base_url=https://api.example.com/?a=b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.FetchBaseURL != "https://api.example.com/?a=b" {
+		t.Errorf("FetchBaseURL = %q", config.FetchBaseURL)
+	}
+
+	// 缺少 = 或参数名为空时报错
+	for param, want := range map[string]string{
+		"flatten":                     `参数 "flatten" 缺少 =`,
+		"lang=js,flatten":             `参数 "flatten" 缺少 =`,
+		"=true":                       `参数 "=true" 缺少参数名`,
+		"output_paths=a:b,c=d,=x":     `参数 "=x" 缺少参数名`,
+		"service_import=./api,  sort": `参数 "sort" 缺少 =`,
+	} {
+		_, err := parsePluginOptions(param)
+		assertErrorContains(t, err, want)
+	}
+}
+
+func TestParseOutputPathsEmptySegments(t *testing.T) {
+	tests := []struct {
+		param string
+		want  []OutputPathConfig
+	}{
+		{"output_paths=src/api;", []OutputPathConfig{{Path: "src/api"}}},
+		{"output_paths=;src/api;;admin/api;", []OutputPathConfig{{Path: "src/api"}, {Path: "admin/api"}}},
+		{"output_paths= src/api : @/request ; ", []OutputPathConfig{{Path: "src/api", ServiceImport: "@/request"}}},
+		// 路径为空的 path:import 忽略；import 为空时使用全局的 service_import
+		{"output_paths=:@/request;src/api:", []OutputPathConfig{{Path: "src/api"}}},
+		{"output_paths=;", nil},
+	}
+	for _, tt := range tests {
+		config, err := parsePluginOptions(tt.param)
+		if err != nil {
+			t.Fatalf("parsePluginOptions(%q): %v", tt.param, err)
+		}
+		if !reflect.DeepEqual(config.OutputPaths, tt.want) {
+			t.Errorf("parsePluginOptions(%q).OutputPaths = %+v, want %+v", tt.param, config.OutputPaths, tt.want)
+		}
+	}
+}
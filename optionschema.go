@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// pluginOption 一个插件参数：Name 为内联参数 / options_file 中的键，
+// Dump 返回该参数在解析后配置中的取值（options_file 可读回的 JSON 值），为 nil 的参数（别名等）不写入 dump_config
+type pluginOption struct {
+	Name string
+	Dump func(config *PluginConfig) interface{}
+}
+
+// pluginOptions 全部插件参数，顺序即 dump_config 中键的顺序；新增参数时须同时在此登记，
+// 否则 dump_config 不会写出该参数，options_file 中的该键也会被视为未知参数
+var pluginOptions = []pluginOption{
+	{"options_file", nil},
+	{"service_import", func(c *PluginConfig) interface{} { return c.ServiceImport }},
+	{"service_import_js", func(c *PluginConfig) interface{} { return c.ServiceImportJS }},
+	{"types_import_path", func(c *PluginConfig) interface{} { return c.TypesImportPath }},
+	{"output_paths", func(c *PluginConfig) interface{} { return dumpOutputPaths(c.OutputPaths) }},
+	{"output_paths_js", func(c *PluginConfig) interface{} { return dumpOutputPaths(c.OutputPathsJS) }},
+	// output_dir 解析后已并入 output_paths / output_paths_js，不再单独写出（否则读回时会提示两者同时配置）
+	{"output_dir", nil},
+	{"lang", func(c *PluginConfig) interface{} { return c.Lang }},
+	{"split_query_types", func(c *PluginConfig) interface{} { return c.SplitQueryTypes }},
+	{"version_in_header", func(c *PluginConfig) interface{} { return c.VersionInHeader }},
+	{"streaming", func(c *PluginConfig) interface{} { return c.Streaming }},
+	{"protocol", func(c *PluginConfig) interface{} { return c.Protocol }},
+	{"lint_ignore", func(c *PluginConfig) interface{} { return dumpList(c.LintIgnore) }},
+	{"semi", func(c *PluginConfig) interface{} { return !c.Style.NoSemi }},
+	{"trailing_comma", func(c *PluginConfig) interface{} { return dumpBoolString(c.Style.TrailingComma) }},
+	{"quote", func(c *PluginConfig) interface{} { return c.Style.Quote }},
+	{"indent", func(c *PluginConfig) interface{} { return dumpIndent(c.Style.Indent) }},
+	{"sort_methods", func(c *PluginConfig) interface{} { return c.SortMethods }},
+	{"emit_paths", func(c *PluginConfig) interface{} { return c.EmitPaths }},
+	{"strip_path_prefix", func(c *PluginConfig) interface{} { return c.StripPathPrefix }},
+	{"path_prefix", func(c *PluginConfig) interface{} { return c.PathPrefix }},
+	{"include_services", func(c *PluginConfig) interface{} { return dumpList(c.ServiceFilter.Include) }},
+	{"exclude_services", func(c *PluginConfig) interface{} { return dumpList(c.ServiceFilter.Exclude) }},
+	{"include_methods", func(c *PluginConfig) interface{} { return dumpList(c.MethodFilter.Include) }},
+	{"exclude_methods", func(c *PluginConfig) interface{} { return dumpList(c.MethodFilter.Exclude) }},
+	{"framework", func(c *PluginConfig) interface{} { return c.Framework }},
+	{"timestamp_type", func(c *PluginConfig) interface{} { return c.TimestampType }},
+	{"emit_interfaces", func(c *PluginConfig) interface{} { return c.EmitInterfaces }},
+	{"param_name", func(c *PluginConfig) interface{} { return c.ParamName }},
+	{"write_response", func(c *PluginConfig) interface{} { return c.WriteResponse }},
+	{"export_style", func(c *PluginConfig) interface{} { return c.ExportStyle }},
+	{"package_dirs", func(c *PluginConfig) interface{} { return c.PackageDirs }},
+	{"banner", func(c *PluginConfig) interface{} { return c.Banner }},
+	{"banner_tool", func(c *PluginConfig) interface{} { return c.BannerTool }},
+	{"strip_suffix", func(c *PluginConfig) interface{} { return dumpStripSuffixes(c.StripSuffixes) }},
+	{"emit_enum_labels", func(c *PluginConfig) interface{} { return c.EmitEnumLabels }},
+	{"hooks", func(c *PluginConfig) interface{} { return c.Hooks }},
+	{"emit_group_tags", func(c *PluginConfig) interface{} { return c.EmitGroupTags }},
+	{"fetch_base_url", func(c *PluginConfig) interface{} { return c.FetchBaseURL }},
+	{"client_style", func(c *PluginConfig) interface{} { return c.ClientStyle }},
+	{"cache_get", func(c *PluginConfig) interface{} { return c.CacheGet }},
+	{"file_ext", func(c *PluginConfig) interface{} { return strings.TrimPrefix(c.FileExt, ".") }},
+	{"incremental", func(c *PluginConfig) interface{} { return c.Incremental }},
+	{"emit_ops_map", func(c *PluginConfig) interface{} { return c.EmitOpsMap }},
+	{"emit_timeout_constants", func(c *PluginConfig) interface{} { return c.EmitTimeoutConstants }},
+	{"get_params", func(c *PluginConfig) interface{} { return c.GetParams }},
+	{"compat_args", func(c *PluginConfig) interface{} { return c.CompatArgs }},
+	{"client", func(c *PluginConfig) interface{} { return c.Client }},
+	{"template", nil}, // client 的别名
+	{"merge_by_package", func(c *PluginConfig) interface{} { return c.MergeByPackage }},
+	{"emit_abort_all", func(c *PluginConfig) interface{} { return c.EmitAbortAll }},
+	{"body_key_case", func(c *PluginConfig) interface{} { return c.BodyKeyCase }},
+	{"call_style", func(c *PluginConfig) interface{} { return c.CallStyle }},
+	{"strict_null", func(c *PluginConfig) interface{} { return c.StrictNull }},
+	{"emit_configure", func(c *PluginConfig) interface{} { return c.EmitConfigure }},
+	{"retry", func(c *PluginConfig) interface{} { return c.Retry }},
+	{"retry_statuses", func(c *PluginConfig) interface{} { return dumpStatuses(c.RetryStatuses) }},
+	{"merge_defaults", func(c *PluginConfig) interface{} { return c.MergeDefaults }},
+	// dump_config 不写出自身，避免以导出的文件作为 options_file 时再次导出
+	{"dump_config", nil},
+	{"emit_zod", func(c *PluginConfig) interface{} { return c.EmitZod }},
+	{"include_internal", func(c *PluginConfig) interface{} { return c.IncludeInternal }},
+	{"emit_package_json", func(c *PluginConfig) interface{} { return c.EmitPackageJSON }},
+	{"index_name", func(c *PluginConfig) interface{} { return c.IndexName }},
+	{"deep_comments", func(c *PluginConfig) interface{} { return c.DeepComments }},
+	{"generate_index", func(c *PluginConfig) interface{} { return c.GenerateIndex }},
+	{"emit_index", nil}, // generate_index 的别名
+	{"typed_pages", func(c *PluginConfig) interface{} { return c.TypedPages }},
+	{"emit_registry_augmentation", func(c *PluginConfig) interface{} { return c.EmitRegistryAugmentation }},
+	{"emit_examples", func(c *PluginConfig) interface{} { return c.EmitExamples }},
+	{"flatten", func(c *PluginConfig) interface{} { return c.Flatten }},
+	{"encode_path_params", func(c *PluginConfig) interface{} { return c.EncodePathParams }},
+	{"emit_result_union", func(c *PluginConfig) interface{} { return c.EmitResultUnion }},
+	{"error_tuple", func(c *PluginConfig) interface{} { return c.ErrorTuple }},
+	{"verify_service_import", func(c *PluginConfig) interface{} { return c.VerifyServiceImport }},
+	{"inline_request_enums", func(c *PluginConfig) interface{} { return c.InlineRequestEnums }},
+	{"arrow_style", func(c *PluginConfig) interface{} { return c.ArrowStyle }},
+	{"emit_request_type_names", func(c *PluginConfig) interface{} { return c.EmitRequestTypeNames }},
+	{"check_only", func(c *PluginConfig) interface{} { return c.CheckOnly }},
+	{"use_json_names", func(c *PluginConfig) interface{} { return c.UseJSONNames }},
+	{"output_zip", func(c *PluginConfig) interface{} { return c.OutputZip }},
+	{"emit_infinite_queries", func(c *PluginConfig) interface{} { return c.EmitInfiniteQueries }},
+	{"first_acronym", func(c *PluginConfig) interface{} { return c.FirstAcronym }},
+	{"verb_response", func(c *PluginConfig) interface{} { return c.VerbResponses }},
+	{"bundle_dts", func(c *PluginConfig) interface{} { return c.BundleDts }},
+	{"emit_path_builders", func(c *PluginConfig) interface{} { return c.EmitPathBuilders }},
+	{"default_verb", func(c *PluginConfig) interface{} { return c.DefaultVerb }},
+	{"emit_enum_helpers", func(c *PluginConfig) interface{} { return c.EmitEnumHelpers }},
+	{"method_client", func(c *PluginConfig) interface{} { return c.MethodClients }},
+}
+
+// isPluginOption 判断是否为已登记的插件参数名
+func isPluginOption(name string) bool {
+	for _, option := range pluginOptions {
+		if option.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalOptions 按 pluginOptions 的顺序将配置写为 JSON 对象，键为参数名，可直接作为 options_file 读回
+func marshalOptions(config *PluginConfig) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	first := true
+	for _, option := range pluginOptions {
+		if option.Dump == nil {
+			continue
+		}
+		value, err := json.MarshalIndent(option.Dump(config), "  ", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		buf.WriteString("\n  " + strconv.Quote(option.Name) + ": ")
+		buf.Write(value)
+	}
+	buf.WriteString("\n}\n")
+	return buf.Bytes(), nil
+}
+
+// dumpOutputPaths 将输出路径写为数组：未单独指定 service_import 的写为路径字符串，否则写为 {"path", "service_import"}
+func dumpOutputPaths(paths []OutputPathConfig) interface{} {
+	items := make([]interface{}, len(paths))
+	for i, p := range paths {
+		if p.ServiceImport == "" {
+			items[i] = p.Path
+		} else {
+			items[i] = map[string]string{"path": p.Path, "service_import": p.ServiceImport}
+		}
+	}
+	return items
+}
+
+// dumpList 将多值参数写为数组，为空时写为 null（保留默认值）
+func dumpList(items []string) interface{} {
+	if len(items) == 0 {
+		return nil
+	}
+	return items
+}
+
+// dumpStripSuffixes 写出 strip_suffix：显式配置为空（strip_suffix=;）时写为 ";"，避免读回时恢复默认的 Service
+func dumpStripSuffixes(suffixes []string) interface{} {
+	if len(suffixes) == 0 {
+		return ";"
+	}
+	return suffixes
+}
+
+// dumpStatuses 将 retry_statuses 写为数组，为空时写为 null
+func dumpStatuses(statuses []int) interface{} {
+	if len(statuses) == 0 {
+		return nil
+	}
+	return statuses
+}
+
+// dumpBoolString 将 "true" / "false" 形式的取值写为布尔值，未配置时写为 null
+func dumpBoolString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value == "true"
+}
+
+// dumpIndent 将缩进写为 indent 参数的取值：tab 或空格数，未配置时写为 null
+func dumpIndent(indent string) interface{} {
+	if indent == "" {
+		return nil
+	}
+	if indent == "\t" {
+		return "tab"
+	}
+	return len(indent)
+}
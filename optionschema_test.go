@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestDumpConfigRoundTrip dump_config 导出的文件作为 options_file 读回后应得到相同的配置
+func TestDumpConfigRoundTrip(t *testing.T) {
+	params := []string{
+		"",
+		"service_import=@/utils/request,service_import_js=@/utils/request-js,types_import_path=@/types",
+		"output_paths=src/api;admin/api:@/admin/request,output_paths_js=js/api",
+		"output_dir=out/api,lang=js",
+		"semi=false,trailing_comma=true,quote=double,indent=tab,lint_ignore=true",
+		"indent=4,trailing_comma=false,semi=true",
+		"include_services=Order*;Goods*,exclude_services=Admin*,include_methods=Get*,exclude_methods=Delete*",
+		"strip_suffix=Service;API,strip_path_prefix=/api/,path_prefix=/v1",
+		"strip_suffix=;",
+		"incremental=true,file_ext=mjs,cache_get=30,retry=2,retry_statuses=502;503,banner=false,banner_tool=gen",
+		"verb_response=delete:void;get:raw,method_client=Order.GetOrder:getRaw;Ping:head,default_verb=POST",
+		"client=fetch,fetch_base_url=https://api.example.com/,get_params=query,client_style=unified",
+		"template=fetch,emit_index=true,index_name=all.ts",
+		"framework=vue,timestamp_type=Date,param_name=req,export_style=named,arrow_style=block,call_style=fluent,first_acronym=preserve",
+		"body_key_case=snake,streaming=stub,protocol=connect,encode_path_params=false",
+		"emit_paths=true,sort_methods=true,emit_interfaces=true,split_query_types=true,strict_null=true,typed_pages=true,use_json_names=true",
+		"emit_enum_labels=true,emit_enum_helpers=true,inline_request_enums=true,emit_request_type_names=true,emit_zod=true",
+		"hooks=swr,emit_registry_augmentation=true,emit_examples=true,emit_group_tags=true,emit_result_union=true,error_tuple=true",
+		"compat_args=true,emit_configure=true,emit_abort_all=true,merge_defaults=true,emit_timeout_constants=true,emit_ops_map=true",
+		"package_dirs=true,merge_by_package=true,flatten=true,generate_index=true,bundle_dts=true,emit_path_builders=true,emit_package_json=true",
+		"write_response=true,include_internal=true,deep_comments=true,verify_service_import=true",
+		"output_zip=api.zip,version_in_header=true,emit_infinite_queries=true,hooks=react-query,check_only=true",
+	}
+	dir := t.TempDir()
+	for i, param := range params {
+		dump := filepath.Join(dir, "config.json")
+		withDump := "dump_config=" + dump
+		if param != "" {
+			withDump = param + "," + withDump
+		}
+		want, err := parsePluginOptions(withDump)
+		if err != nil {
+			t.Fatalf("#%d parsePluginOptions(%q): %v", i, param, err)
+		}
+		if err := dumpConfig(want); err != nil {
+			t.Fatalf("#%d dumpConfig: %v", i, err)
+		}
+		got, err := parsePluginOptions("options_file=" + dump)
+		if err != nil {
+			data, _ := os.ReadFile(dump)
+			t.Fatalf("#%d 读回 %s 失败: %v", i, data, err)
+		}
+		// output_dir 已并入 output_paths / output_paths_js，不单独写出
+		want.DumpConfig, want.OutputDir = "", ""
+		if !reflect.DeepEqual(got, want) {
+			data, _ := os.ReadFile(dump)
+			t.Errorf("#%d %q 读回后不一致\ndump: %s\ngot:  %+v\nwant: %+v", i, param, data, got, want)
+		}
+	}
+}
+
+// TestPluginOptionsCoverParser parsePluginOptions 中的每个参数都应在 pluginOptions 中登记
+func TestPluginOptionsCoverParser(t *testing.T) {
+	src, err := os.ReadFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(src)
+	start := strings.Index(body, "func parsePluginOptions(")
+	if start < 0 {
+		t.Fatal("找不到 parsePluginOptions")
+	}
+	body = body[start:]
+	body = body[:strings.Index(body, "\n}\n")]
+	caseLine := regexp.MustCompile(`(?m)^\t\tcase (.+):$`)
+	name := regexp.MustCompile(`"([a-z_]+)"`)
+	parsed := map[string]bool{}
+	for _, m := range caseLine.FindAllStringSubmatch(body, -1) {
+		for _, n := range name.FindAllStringSubmatch(m[1], -1) {
+			parsed[n[1]] = true
+			if !isPluginOption(n[1]) {
+				t.Errorf("参数 %s 未在 pluginOptions 中登记", n[1])
+			}
+		}
+	}
+	for _, option := range pluginOptions {
+		if !parsed[option.Name] {
+			t.Errorf("pluginOptions 中的 %s 不是 parsePluginOptions 解析的参数", option.Name)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// optionPair 一个插件参数（key=value）
+type optionPair struct {
+	Key   string
+	Value string
+}
+
+// loadOptionsFile 读取 options_file 指定的 JSON 配置，转换为与内联参数相同的 key=value 形式，按键名排序
+// 键与内联参数名相同，值可以是字符串、布尔值或数字；多值参数（output_paths、strip_suffix 等）可写为数组，
+// output_paths / output_paths_js 的数组元素还可写为 {"path": "...", "service_import": "..."}；
+// 映射参数（verb_response、method_client 等）可写为对象
+func loadOptionsFile(path string) ([]optionPair, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 options_file 失败 %s: %v", path, err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析 options_file 失败 %s: %v", path, err)
+	}
+
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []optionPair
+	for _, key := range keys {
+		if key == "options_file" {
+			return nil, fmt.Errorf("options_file %s 中不能再指定 options_file", path)
+		}
+		if !isPluginOption(key) {
+			logf("警告: options_file %s 中的参数 %s 未知，已忽略", path, key)
+		}
+		value, err := optionValue(doc[key])
+		if err != nil {
+			return nil, fmt.Errorf("options_file %s 中参数 %s 的值无效: %v", path, key, err)
+		}
+		pairs = append(pairs, optionPair{Key: key, Value: value})
+	}
+	return pairs, nil
+}
+
+// optionValue 将 JSON 值转换为内联参数的值：数组以 ; 连接，对象转为 key:value;key:value，null 等同于空值（保留默认值）
+func optionValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			var value string
+			var err error
+			if path, ok := item.(map[string]interface{}); ok {
+				value, err = outputPathValue(path)
+			} else {
+				value, err = optionValue(item)
+			}
+			if err != nil {
+				return "", err
+			}
+			items = append(items, value)
+		}
+		return strings.Join(items, ";"), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(keys))
+		for _, key := range keys {
+			value, err := optionValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, key+":"+value)
+		}
+		return strings.Join(items, ";"), nil
+	}
+	return "", fmt.Errorf("不支持的类型 %T", v)
+}
+
+// outputPathValue 将 {"path": "...", "service_import": "..."}（对应 OutputPathConfig）转换为 path 或 path:import
+func outputPathValue(v map[string]interface{}) (string, error) {
+	path, ok := v["path"].(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("输出路径缺少 path")
+	}
+	if serviceImport, ok := v["service_import"].(string); ok && serviceImport != "" {
+		return path + ":" + serviceImport, nil
+	}
+	return path, nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// outputWriter 负责写出生成文件：默认直接写入输出目录；配置 output_zip 时先收集到内存，最后统一打包为 zip
+type outputWriter struct {
+	zipPath string            // zip 文件路径，为空表示直接写入磁盘
+	files   map[string][]byte // 打包模式下收集的文件（zip 内路径 -> 内容）
+	roots   map[string]string // 打包模式下 zip 内的顶层目录 -> 对应的输出目录，用于发现映射到同一目录的不同输出路径
+	discard bool              // 只校验（check_only）时丢弃所有输出
+
+	gen *protogen.Plugin // write_response 时通过 CodeGeneratorResponse 返回文件，为 nil 时直接写入磁盘
+
+	style codeStyle // quote / indent / semi 指定的代码风格，写出 TS/JS 文件前按此改写
+
+	incremental bool                    // 是否按清单跳过内容未变化的文件（incremental）
+	manifests   map[string]*dirManifest // 输出目录 -> 生成清单
+	stats       incrementalStats        // 增量生成统计
+}
+
+// newOutputWriter 创建输出写入器，zipPath 为空时直接写入磁盘
+func newOutputWriter(zipPath string) *outputWriter {
+	return &outputWriter{
+		zipPath:   zipPath,
+		files:     make(map[string][]byte),
+		roots:     make(map[string]string),
+		manifests: make(map[string]*dirManifest),
+	}
+}
+
+// write 将 code 写入 dir/name，统一保证文件以且仅以一个换行符结尾；TS/JS 文件（非 .json）按 quote / indent / semi 改写
+// name 可包含以 / 分隔的子目录（如 shop/v1/orderApi.ts），写入磁盘时自动创建子目录，打包时保留为 zip 内的目录层级
+// 直接写入磁盘时若输出目录不存在，跳过该文件，不报错
+func (w *outputWriter) write(dir, name string, code []byte) error {
+	if w.discard {
+		return nil
+	}
+	if !strings.HasSuffix(name, ".json") {
+		code = []byte(formatCode(string(code), w.style))
+	}
+	code = append(bytes.TrimRight(code, "\n"), '\n')
+
+	if w.zipPath != "" {
+		entry, err := w.zipEntry(dir, name)
+		if err != nil {
+			return err
+		}
+		w.files[entry] = code
+		return nil
+	}
+
+	// 交给 protoc 按响应写出（由 protoc / buf / bazel 创建目录），输出目录是否存在不再检查
+	if w.gen != nil {
+		if rel, ok := responsePath(filepath.Join(dir, filepath.FromSlash(name))); ok {
+			if _, err := w.gen.NewGeneratedFile(rel, "").Write(code); err != nil {
+				return fmt.Errorf("写入响应失败 %s: %v", rel, err)
+			}
+			return nil
+		}
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("检查输出目录失败 %s: %v", dir, err)
+	}
+	if w.incremental {
+		if w.unchanged(dir, name, code) {
+			w.stats.skipped++
+			return nil
+		}
+		w.stats.written++
+	}
+	fullPath := filepath.Join(dir, filepath.FromSlash(name))
+	if sub := filepath.Dir(fullPath); sub != filepath.Clean(dir) {
+		if err := os.MkdirAll(sub, 0755); err != nil {
+			return fmt.Errorf("创建子目录失败 %s: %v", sub, err)
+		}
+	}
+	if err := os.WriteFile(fullPath, code, 0644); err != nil {
+		return fmt.Errorf("写入文件失败 %s: %v", fullPath, err)
+	}
+	return nil
+}
+
+// zipEntry 返回文件在 zip 内的路径（/ 分隔的相对路径）：输出目录为相对路径时去掉开头的 ../ 作为顶层目录（../web/src/api -> web/src/api），
+// 为绝对路径时只取最后一级目录名（/abs/out -> out），不同输出目录映射到同一顶层目录、或路径解压后会落到解压目录之外时报错
+func (w *outputWriter) zipEntry(dir, name string) (string, error) {
+	root := path.Clean(filepath.ToSlash(dir))
+	if filepath.IsAbs(dir) {
+		root = path.Base(root)
+	}
+	for root == ".." || strings.HasPrefix(root, "../") {
+		root = strings.TrimPrefix(strings.TrimPrefix(root, ".."), "/")
+	}
+	if root == "" || root == "/" {
+		root = "."
+	}
+	if other, ok := w.roots[root]; ok && other != dir {
+		return "", fmt.Errorf("输出目录 %s 与 %s 在 zip 中都对应 %s，请调整输出路径", other, dir, root)
+	}
+	w.roots[root] = dir
+
+	entry := path.Clean(root + "/" + name)
+	if entry == ".." || strings.HasPrefix(entry, "../") || path.IsAbs(entry) {
+		return "", fmt.Errorf("文件 %s 在 zip 中的路径 %s 位于解压目录之外", name, entry)
+	}
+	return entry, nil
+}
+
+// responsePath 返回文件在 CodeGeneratorResponse 中的路径（相对生成根目录，即当前目录，使用 / 分隔）
+// 绝对路径转为相对当前目录的路径；位于当前目录之外时返回 false，由调用方直接写入磁盘
+func responsePath(fullPath string) (string, bool) {
+	if filepath.IsAbs(fullPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", false
+		}
+		rel, err := filepath.Rel(wd, fullPath)
+		if err != nil {
+			return "", false
+		}
+		fullPath = rel
+	}
+	rel := filepath.ToSlash(filepath.Clean(fullPath))
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
+
+// writeResponseFiles 将响应中的文件写入当前目录（独立模式下没有 protoc 代为写出）
+func writeResponseFiles(gen *protogen.Plugin) error {
+	for _, file := range gen.Response().GetFile() {
+		fullPath := filepath.FromSlash(file.GetName())
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("创建目录失败 %s: %v", filepath.Dir(fullPath), err)
+		}
+		if err := os.WriteFile(fullPath, []byte(file.GetContent()), 0644); err != nil {
+			return fmt.Errorf("写入文件失败 %s: %v", fullPath, err)
+		}
+	}
+	return nil
+}
+
+// flush 打包模式下将收集的文件写入 zip；条目按路径排序并使用固定时间，保证相同输入生成相同的 zip
+// incremental 模式下删除过期文件并写入新的清单
+func (w *outputWriter) flush() error {
+	if w.incremental && w.zipPath == "" && !w.discard {
+		return w.flushManifests()
+	}
+	if w.zipPath == "" || w.discard {
+		return nil
+	}
+
+	entries := make([]string, 0, len(w.files))
+	for entry := range w.files {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	modified := time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, entry := range entries {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     entry,
+			Method:   zip.Deflate,
+			Modified: modified,
+		})
+		if err != nil {
+			return fmt.Errorf("写入 zip 失败 %s: %v", entry, err)
+		}
+		if _, err := fw.Write(w.files[entry]); err != nil {
+			return fmt.Errorf("写入 zip 失败 %s: %v", entry, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("写入 zip 失败 %s: %v", w.zipPath, err)
+	}
+
+	if dir := filepath.Dir(w.zipPath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("创建 zip 所在目录失败 %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(w.zipPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("写入 zip 失败 %s: %v", w.zipPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratedFilesEndWithOneNewline(t *testing.T) {
+	styles := []string{
+		"",
+		"export_style=named",
+		"arrow_style=block",
+		"call_style=fluent",
+		"hooks=react-query,emit_infinite_queries=true,typed_pages=true",
+		"hooks=swr",
+		"framework=vue",
+		"client=fetch",
+		"protocol=connect",
+		"emit_zod=true,emit_examples=true,emit_paths=true,emit_path_builders=true",
+		"emit_interfaces=true,bundle_dts=true",
+		"split_query_types=true,emit_result_union=true,emit_ops_map=true,strict_null=true",
+		"generate_index=true,emit_package_json=true",
+		"flatten=true",
+		"merge_by_package=true",
+		"semi=false,indent=tab,quote=double,trailing_comma=true",
+		"banner=false,lint_ignore=true",
+	}
+	file := orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")
+	for _, style := range styles {
+		param := "output_paths=ts,output_paths_js=js"
+		if style != "" {
+			param += "," + style
+		}
+		generated := mustRunPlugin(t, param, file)
+		if len(generated) == 0 {
+			t.Errorf("%s: 没有生成文件", style)
+		}
+		for name, code := range generated {
+			if !strings.HasSuffix(code, "\n") || strings.HasSuffix(code, "\n\n") {
+				t.Errorf("%s: %s 应以且仅以一个换行符结尾: %q", style, name, code[max(0, len(code)-20):])
+			}
+		}
+	}
+}
+
+func TestOutputWriterNormalizesTrailingNewline(t *testing.T) {
+	out := newOutputWriter("api.zip")
+	for name, code := range map[string]string{
+		"none.ts":  "export {};",
+		"many.ts":  "export {};\n\n\n",
+		"one.json": "{}\n",
+	} {
+		if err := out.write("ts", name, []byte(code)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for entry, code := range out.files {
+		if !strings.HasSuffix(string(code), "\n") || strings.HasSuffix(string(code), "\n\n") {
+			t.Errorf("%s 应以且仅以一个换行符结尾: %q", entry, code)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPackageDirsResponsePaths(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js,package_dirs=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"))
+	// 经 CodeGeneratorResponse 写出时文件名带子目录，使用 / 分隔
+	code := generatedFile(t, generated, "ts/shop/v1/orderApi.ts")
+	assertContains(t, code, "import service from '../../api';")
+	generatedFile(t, generated, "js/shop/v1/orderApi.js")
+	if _, ok := generated["ts/orderApi.ts"]; ok {
+		t.Error("package_dirs 时不应在输出目录根部生成服务文件")
+	}
+}
+
+func TestPackageDirsOnDisk(t *testing.T) {
+	// 输出目录位于生成根目录之外时直接写入磁盘，同样创建子目录
+	dir := t.TempDir()
+	mustRunPlugin(t, "output_paths="+dir+",package_dirs=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"))
+	data, err := os.ReadFile(filepath.Join(dir, "shop", "v1", "orderApi.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, string(data), "export const orderApi = {")
+}
+
+func TestPackageDirsInZip(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "api.zip")
+	if _, err := execPlugin("output_paths=ts,package_dirs=true,output_zip="+archive,
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	if len(names) != 1 || names[0] != "ts/shop/v1/orderApi.ts" {
+		t.Errorf("zip 条目 = %v, want [ts/shop/v1/orderApi.ts]", names)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// packageExport package.json exports 中的一项：子路径及其指向的文件
+type packageExport struct {
+	Subpath string // 如 . 或 ./orderApi
+	File    string // 如 ./orderApi.js
+	Types   string // 类型声明文件，为空时不写 types 条件
+}
+
+// packageExports 返回输出目录中可导出的模块：汇总入口（generate_index / flatten）作为 .，每个服务文件（merge_by_package 时为每个合并文件）作为 ./xxxApi
+// ext 为文件扩展名（.ts 或 file_ext）；JS 目录开启 bundle_dts 时入口带上 api.d.ts 类型声明
+func packageExports(services []*ServiceInfo, config *PluginConfig, ext string) []packageExport {
+	var exports []packageExport
+	types := ""
+	if ext != ".ts" && config.BundleDts {
+		types = "./api.d.ts"
+	}
+	switch {
+	case config.Flatten:
+		exports = append(exports, packageExport{Subpath: ".", File: "./" + flatFileName + ext, Types: types})
+		return exports
+	case config.MergeByPackage:
+		for _, group := range groupByPackage(services) {
+			exports = append(exports, packageExport{Subpath: "./" + group.FileName, File: "./" + group.FileName + ext})
+		}
+		return exports
+	case config.GenerateIndex:
+		exports = append(exports, packageExport{Subpath: ".", File: "./" + config.IndexName + ext, Types: types})
+	}
+	for _, svc := range sortedByFileName(services) {
+		exports = append(exports, packageExport{Subpath: "./" + serviceFilePath(*svc), File: "./" + serviceFilePath(*svc) + ext})
+	}
+	return exports
+}
+
+// generatePackageJSON 生成输出目录的 package.json 片段：声明 ESM（type: module）及 exports，
+// 每个子路径提供 import 与 default 条件（default 供不识别 import 条件的打包工具使用），均指向同一个 ESM 文件；
+// 生成的代码只有 ESM 形式，不提供 require 条件，CommonJS 中需以 import() 动态导入
+// 手写 JSON 以保证条件顺序（types 在前、default 在后，Node 按声明顺序匹配条件）
+func generatePackageJSON(exports []packageExport) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	buf.WriteString("  \"type\": \"module\",\n")
+	buf.WriteString("  \"sideEffects\": false,\n")
+	buf.WriteString("  \"exports\": {")
+	for i, e := range exports {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n    ")
+		buf.WriteString(strconv.Quote(e.Subpath))
+		buf.WriteString(": {\n")
+		if e.Types != "" {
+			buf.WriteString("      \"types\": ")
+			buf.WriteString(strconv.Quote(e.Types))
+			buf.WriteString(",\n")
+		}
+		buf.WriteString("      \"import\": ")
+		buf.WriteString(strconv.Quote(e.File))
+		buf.WriteString(",\n")
+		buf.WriteString("      \"default\": ")
+		buf.WriteString(strconv.Quote(e.File))
+		buf.WriteString("\n    }")
+	}
+	if len(exports) > 0 {
+		buf.WriteString("\n  ")
+	}
+	buf.WriteString("}\n")
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// pageItemsOf 判断响应是否为标准分页结构：只包含 repeated 消息字段 items 和 string 字段 next_page_token
+// 是则返回列表元素的消息，否则返回 nil（含其他字段时不视为分页结构，避免 Page<T> 丢失字段类型）
+func pageItemsOf(output *protogen.Message) *protogen.Message {
+	if output == nil || len(output.Fields) != 2 {
+		return nil
+	}
+	var items *protogen.Message
+	hasToken := false
+	for _, field := range output.Fields {
+		switch string(field.Desc.Name()) {
+		case "items":
+			if field.Desc.IsList() && field.Message != nil {
+				items = field.Message
+			}
+		case "next_page_token":
+			hasToken = field.Desc.Kind() == protoreflect.StringKind && !field.Desc.IsList()
+		}
+	}
+	if !hasToken {
+		return nil
+	}
+	return items
+}
+
+// pageKeys 返回 Page<T> 中列表和下一页 token 的键名，取第一个分页方法的键名（与 use_json_names 一致）
+func pageKeys(services ...ServiceInfo) (itemsKey, tokenKey string, ok bool) {
+	for _, svc := range services {
+		for _, method := range svc.Methods {
+			if method.PageItemType != "" {
+				return method.PageItemsKey, method.NextPageTokenKey, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// writePageType 存在 typed_pages 分页方法时生成通用分页类型 Page<T>
+func writePageType(buf *bytes.Buffer, services ...ServiceInfo) {
+	itemsKey, tokenKey, ok := pageKeys(services...)
+	if !ok {
+		return
+	}
+	buf.WriteString("export type Page<T> = {\n")
+	buf.WriteString("  ")
+	buf.WriteString(itemsKey)
+	buf.WriteString(": T[];\n")
+	buf.WriteString("  ")
+	buf.WriteString(tokenKey)
+	buf.WriteString(": string;\n")
+	buf.WriteString("};\n\n")
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// objectFile 返回路径中含单段变量与 ** 通配变量的存储服务
+func objectFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("storage/v1/object.proto", "storage.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("GetObjectReq",
+				protoField("bucket", 1, typeString, ""),
+				protoField("path", 2, typeString, ""),
+			),
+		},
+		protoService("ObjectService",
+			protoMethod("GetFile", ".storage.v1.GetObjectReq", ".google.protobuf.Empty", httpGet("/v1/files/{path=**}")),
+			protoMethod("GetObject", ".storage.v1.GetObjectReq", ".google.protobuf.Empty", httpGet("/v1/buckets/{bucket}/objects/{path=**}")),
+		),
+	)
+}
+
+func TestCatchAllPathVariable(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js", objectFile())
+	// ** 变量转发剩余的整段路径，不编码其中的 /；其余单段变量照常编码
+	assertContains(t, generatedFile(t, generated, "ts/objectApi.ts"),
+		"service.get(`/v1/files/${data.path}`, data)",
+		"service.get(`/v1/buckets/${encodeURIComponent(data.bucket)}/objects/${data.path}`, data)",
+	)
+	assertContains(t, generatedFile(t, generated, "js/objectApi.js"),
+		"GetFile: (data) => service.get(`/v1/files/${data.path}`, data)",
+	)
+	assertNotContains(t, generatedFile(t, generated, "ts/objectApi.ts"), "encodeURIComponent(data.path)", "{path=**}")
+}
+
+func TestCatchAllPathTemplate(t *testing.T) {
+	if err := validatePathTemplate("/v1/buckets/{bucket}/objects/{path=**}"); err != nil {
+		t.Errorf("validatePathTemplate: %v", err)
+	}
+	_, vars := parsePathTemplate("/v1/buckets/{bucket}/objects/{path=**}")
+	if len(vars) != 2 || vars[0].FieldPath != "bucket" || vars[1].FieldPath != "path" {
+		t.Errorf("parsePathTemplate 变量 = %+v", vars)
+	}
+}
+
+func TestEncodePathParams(t *testing.T) {
+	file := orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")
+
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js", file)
+	assertContains(t, generatedFile(t, generated, "ts/orderApi.ts"),
+		"service.get(`/v1/shop/orders/${encodeURIComponent(data.orderId)}`, data)",
+		"service.post(`/v1/shop/shops/${encodeURIComponent(data.shopId)}/orders`, data.order",
+	)
+	assertContains(t, generatedFile(t, generated, "js/orderApi.js"),
+		"service.get(`/v1/shop/orders/${encodeURIComponent(data.orderId)}`, data)",
+	)
+
+	generated = mustRunPlugin(t, "output_paths=ts,output_paths_js=js,encode_path_params=false", file)
+	for _, name := range []string{"ts/orderApi.ts", "js/orderApi.js"} {
+		code := generatedFile(t, generated, name)
+		assertContains(t, code, "service.get(`/v1/shop/orders/${data.orderId}`, data)")
+		assertNotContains(t, code, "encodeURIComponent")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// goodsFile 返回同时含字面量路径与带变量路径的商品服务
+func goodsFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("shop/v1/goods.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("Goods", protoField("goods_id", 1, typeString, "")),
+			protoMessage("GetGoodsReq", protoField("goods_id", 1, typeString, "")),
+			protoMessage("ListGoodsReq", protoField("keyword", 1, typeString, "")),
+		},
+		protoService("GoodsService",
+			protoMethod("ListGoods", ".shop.v1.ListGoodsReq", ".shop.v1.Goods", httpGet("/v1/goods")),
+			protoMethod("GetGoods", ".shop.v1.GetGoodsReq", ".shop.v1.Goods", httpGet("/v1/goods/{goods_id}")),
+		),
+	)
+}
+
+func TestPathPrefix(t *testing.T) {
+	// 前缀末尾及路径开头的 / 只保留一个
+	for _, prefix := range []string{"/api", "/api/", "/api//"} {
+		generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js,path_prefix="+prefix, goodsFile())
+		for _, name := range []string{"ts/goodsApi.ts", "js/goodsApi.js"} {
+			code := generatedFile(t, generated, name)
+			assertContains(t, code,
+				"service.get('/api/v1/goods', data)",
+				"service.get(`/api/v1/goods/${encodeURIComponent(data.goodsId)}`, data)",
+			)
+			assertNotContains(t, code, "//v1", "'/v1/goods'")
+		}
+	}
+}
+
+func TestPathPrefixWithStripPathPrefix(t *testing.T) {
+	// 先去掉 strip_path_prefix，再拼接 path_prefix
+	generated := mustRunPlugin(t, "output_paths=ts,strip_path_prefix=/v1,path_prefix=/api", goodsFile())
+	assertContains(t, generatedFile(t, generated, "ts/goodsApi.ts"),
+		"service.get('/api/goods', data)",
+		"service.get(`/api/goods/${encodeURIComponent(data.goodsId)}`, data)",
+	)
+}
+
+func TestPathPrefixEmpty(t *testing.T) {
+	for _, param := range []string{"output_paths=ts", "output_paths=ts,path_prefix=", "output_paths=ts,path_prefix=/"} {
+		generated := mustRunPlugin(t, param, goodsFile())
+		assertContains(t, generatedFile(t, generated, "ts/goodsApi.ts"), "service.get('/v1/goods', data)")
+	}
+}
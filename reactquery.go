@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// writeReactQueryImport 写入 @tanstack/react-query 的导入（没有需要生成的 hook 时不写）
+func writeReactQueryImport(buf *bytes.Buffer, data ServiceInfo) {
+	var names []string
+	if hasInfiniteQueries(data) {
+		names = append(names, "useInfiniteQuery")
+	}
+	if data.Hooks == "react-query" {
+		for _, method := range data.Methods {
+			if method.HttpMethod == "get" {
+				names = append(names, "useQuery")
+				break
+			}
+		}
+		for _, method := range data.Methods {
+			if method.HttpMethod != "get" {
+				names = append(names, "useMutation")
+				break
+			}
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	buf.WriteString("import { ")
+	buf.WriteString(strings.Join(names, ", "))
+	buf.WriteString(" } from '@tanstack/react-query';\n")
+}
+
+// hasInfiniteQueries 判断是否需要生成 useInfiniteXxx hook
+func hasInfiniteQueries(data ServiceInfo) bool {
+	if !data.EmitInfiniteQueries {
+		return false
+	}
+	for _, method := range data.Methods {
+		if method.Paginated {
+			return true
+		}
+	}
+	return false
+}
+
+// writeInfiniteQueryHooks 为分页方法生成 useInfiniteXxx hook（React Query v5）
+// 调用方传入除 pageToken 外的请求参数，翻页时以上一页的 nextPageToken 作为 pageToken（键名见 fieldKey）
+// typed 为 true 时生成 TS 类型标注，indent 为每层缩进
+func writeInfiniteQueryHooks(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if !hasInfiniteQueries(data) {
+		return
+	}
+	in1, in2 := indent, indent+indent
+	for _, method := range data.Methods {
+		if !method.Paginated {
+			continue
+		}
+		buf.WriteString("export const useInfinite")
+		buf.WriteString(method.MethodName)
+		if typed {
+			buf.WriteString(" = (data: Omit<")
+			buf.WriteString(method.RequestType)
+			buf.WriteString(", '")
+			buf.WriteString(method.PageTokenKey)
+			buf.WriteString("'>) =>\n")
+		} else {
+			buf.WriteString(" = (data) =>\n")
+		}
+		buf.WriteString(in1 + "useInfiniteQuery({\n")
+		buf.WriteString(in2 + "queryKey: ['")
+		buf.WriteString(data.ApiFileName)
+		buf.WriteString("', '")
+		buf.WriteString(method.MethodName)
+		buf.WriteString("', data],\n")
+		buf.WriteString(in2 + "queryFn: ({ pageParam }) => ")
+		buf.WriteString(data.ApiFileName)
+		buf.WriteString(".")
+		buf.WriteString(method.MethodName)
+		buf.WriteString("({ ...data, ")
+		buf.WriteString(method.PageTokenKey)
+		buf.WriteString(": pageParam })")
+		buf.WriteString(unwrapErrorTuple(data, method, typed, in2, indent))
+		buf.WriteString(",\n")
+		buf.WriteString(in2 + "initialPageParam: '',\n")
+		if typed {
+			buf.WriteString(in2 + "getNextPageParam: (lastPage: ")
+			buf.WriteString(responseType(data, method))
+			buf.WriteString(") => lastPage.")
+		} else {
+			buf.WriteString(in2 + "getNextPageParam: (lastPage) => lastPage.")
+		}
+		buf.WriteString(method.NextPageTokenKey)
+		buf.WriteString(" || undefined")
+		buf.WriteString(memberEnd(true, data.TrailingComma))
+		buf.WriteString(in1 + "});\n\n")
+	}
+}
+
+// unwrapErrorTuple error_tuple 模式下方法不抛出异常，返回解开元组的 .then(...)：出错时抛出，交由 React Query / SWR 处理
+// indent 为 .then 所在属性的缩进，step 为每层缩进；非 error_tuple 时返回空串
+func unwrapErrorTuple(data ServiceInfo, method MethodInfo, typed bool, indent, step string) string {
+	if !data.ErrorTuple {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(".then(([err, res]) => {\n")
+	b.WriteString(indent + step + "if (err) throw err;\n")
+	b.WriteString(indent + step + "return res")
+	if typed {
+		b.WriteString(" as " + responseType(data, method))
+	}
+	b.WriteString(";\n")
+	b.WriteString(indent + "})")
+	return b.String()
+}
+
+// queryHookName 返回 hooks=react-query / swr 时方法对应的 hook 名（例如：GetOrder -> useGetOrder）
+func queryHookName(method MethodInfo) string {
+	return "use" + method.MethodName
+}
+
+// writeQueryHooks hooks=react-query 时为每个方法生成 hook（React Query v5）：
+// GET 方法生成 useQuery（queryKey 为 [文件名, 方法名, data]），其余方法生成 useMutation（mutationFn 接收请求数据）；
+// 没有请求参数的方法 hook 与 mutationFn 均不接收参数，queryKey 不含 data
+// typed 为 true 时生成 TS 类型标注，indent 为每层缩进
+func writeQueryHooks(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if data.Hooks != "react-query" {
+		return
+	}
+	in1, in2 := indent, indent+indent
+	for _, method := range data.Methods {
+		param, arg, key := "data", "data", ", data"
+		if typed {
+			param = "data: " + requestParamType(data, method)
+		}
+		if method.NoRequest {
+			param, arg, key = "", "", ""
+		}
+		call := data.ApiFileName + "." + method.MethodName + "(" + arg + ")" + unwrapErrorTuple(data, method, typed, in2, indent)
+		buf.WriteString("export const ")
+		buf.WriteString(queryHookName(method))
+		if method.HttpMethod == "get" {
+			buf.WriteString(" = (" + param + ") =>\n")
+			buf.WriteString(in1 + "useQuery({\n")
+			buf.WriteString(in2 + "queryKey: ['" + data.ApiFileName + "', '" + method.MethodName + "'" + key + "],\n")
+			buf.WriteString(in2 + "queryFn: () => " + call + memberEnd(true, data.TrailingComma))
+		} else {
+			buf.WriteString(" = () =>\n")
+			buf.WriteString(in1 + "useMutation({\n")
+			buf.WriteString(in2 + "mutationFn: (" + param + ") => " + call + memberEnd(true, data.TrailingComma))
+		}
+		buf.WriteString(in1 + "});\n\n")
+	}
+}
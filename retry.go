@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseRetryStatuses 解析 retry_statuses（如 502;503;504 或 502 503 504），忽略空项
+func parseRetryStatuses(value string) ([]int, error) {
+	var statuses []int
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ' ' || r == '|' }) {
+		status, err := strconv.Atoi(item)
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("retry_statuses 中的状态码无效: %s", item)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// wrapRetry 开启 retry 时将调用包裹为 withRetry(() => call)
+func wrapRetry(data ServiceInfo, call string) string {
+	if data.Retry <= 0 {
+		return call
+	}
+	return "withRetry(() => " + call + ")"
+}
+
+// writeRetryHelper 开启 retry 时生成模块内的 withRetry 辅助函数：失败后最多重试 retry 次，
+// 配置了 retry_statuses 时只在错误状态码（axios 的 err.response.status 或 err.status）属于其中时重试，避免重试 4xx 等客户端错误
+// typed 为 true 时生成 TS 类型标注，indent 为每层缩进
+func writeRetryHelper(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if data.Retry <= 0 {
+		return
+	}
+	statuses := make([]string, len(data.RetryStatuses))
+	for i, status := range data.RetryStatuses {
+		statuses[i] = strconv.Itoa(status)
+	}
+	in1, in2, in3, in4 := indent, indent+indent, indent+indent+indent, indent+indent+indent+indent
+
+	buf.WriteString("const RETRY_STATUSES")
+	if typed {
+		buf.WriteString(": number[]")
+	}
+	buf.WriteString(" = [")
+	buf.WriteString(strings.Join(statuses, ", "))
+	buf.WriteString("];\n\n")
+
+	if typed {
+		buf.WriteString("const withRetry = async <T>(call: () => Promise<T>, retries = ")
+	} else {
+		buf.WriteString("const withRetry = async (call, retries = ")
+	}
+	buf.WriteString(strconv.Itoa(data.Retry))
+	if typed {
+		buf.WriteString("): Promise<T> => {\n")
+	} else {
+		buf.WriteString(") => {\n")
+	}
+	buf.WriteString(in1 + "for (let attempt = 0; ; attempt++) {\n")
+	buf.WriteString(in2 + "try {\n")
+	buf.WriteString(in3 + "return await call();\n")
+	buf.WriteString(in2 + "} catch (err) {\n")
+	if typed {
+		buf.WriteString(in3 + "const e = err as { status?: number; response?: { status?: number } };\n")
+		buf.WriteString(in3 + "const status = e?.response?.status ?? e?.status;\n")
+	} else {
+		buf.WriteString(in3 + "const status = err?.response?.status ?? err?.status;\n")
+	}
+	buf.WriteString(in3 + "if (attempt >= retries || (RETRY_STATUSES.length > 0 && !RETRY_STATUSES.includes(status")
+	if typed {
+		buf.WriteString(" ?? 0")
+	}
+	buf.WriteString("))) {\n")
+	buf.WriteString(in4 + "throw err;\n")
+	buf.WriteString(in3 + "}\n")
+	buf.WriteString(in2 + "}\n")
+	buf.WriteString(in1 + "}\n")
+	buf.WriteString("};\n\n")
+}
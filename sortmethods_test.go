@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reorderedFile 返回按 names 顺序声明方法的书籍服务
+func reorderedFile(names ...string) *descriptorpb.FileDescriptorProto {
+	var methods []*descriptorpb.MethodDescriptorProto
+	for _, name := range names {
+		methods = append(methods, protoMethod(name, ".library.v1.BookReq", ".library.v1.BookReq", httpGet("/v1/books/"+strings.ToLower(name))))
+	}
+	return protoFile("library/v1/book.proto", "library.v1",
+		[]*descriptorpb.DescriptorProto{protoMessage("BookReq", protoField("book_id", 1, typeString, ""))},
+		protoService("BookService", methods...),
+	)
+}
+
+// methodOrder 返回 JS 代码中各方法按出现位置排列的方法名
+func methodOrder(code string, names ...string) []string {
+	var order []string
+	for pos := 0; ; {
+		next, nextName := -1, ""
+		for _, name := range names {
+			if i := strings.Index(code[pos:], "    "+name+": "); i >= 0 && (next < 0 || i < next) {
+				next, nextName = i, name
+			}
+		}
+		if next < 0 {
+			return order
+		}
+		order = append(order, nextName)
+		pos += next + 1
+	}
+}
+
+func TestSortMethods(t *testing.T) {
+	names := []string{"UpdateBook", "CreateBook", "GetBook", "DeleteBook"}
+	sorted := mustRunPlugin(t, "output_paths_js=js,sort_methods=true", reorderedFile(names...))
+	code := generatedFile(t, sorted, "js/bookApi.js")
+	if got := strings.Join(methodOrder(code, names...), ","); got != "CreateBook,DeleteBook,GetBook,UpdateBook" {
+		t.Errorf("sort_methods=true 方法顺序 = %s", got)
+	}
+
+	// 调整 proto 中的声明顺序，生成结果不变
+	reordered := mustRunPlugin(t, "output_paths_js=js,sort_methods=true", reorderedFile("GetBook", "DeleteBook", "UpdateBook", "CreateBook"))
+	if generatedFile(t, reordered, "js/bookApi.js") != code {
+		t.Error("sort_methods=true 时调整声明顺序不应改变生成结果")
+	}
+}
+
+func TestDeclarationOrderByDefault(t *testing.T) {
+	names := []string{"UpdateBook", "CreateBook", "GetBook", "DeleteBook"}
+	for _, param := range []string{"output_paths_js=js", "output_paths_js=js,sort_methods=false"} {
+		generated := mustRunPlugin(t, param, reorderedFile(names...))
+		if got := strings.Join(methodOrder(generatedFile(t, generated, "js/bookApi.js"), names...), ","); got != strings.Join(names, ",") {
+			t.Errorf("%s: 方法顺序 = %s，应保持声明顺序", param, got)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// validateStreaming 校验 streaming 的取值
+func validateStreaming(value string) error {
+	if value != "skip" && value != "stub" {
+		return fmt.Errorf("streaming 只支持 skip、stub: %s", value)
+	}
+	return nil
+}
+
+// streamKind 返回方法的流式类型：server（服务端流）、client（客户端流）、bidi（双向流），一元方法为空
+func streamKind(method *protogen.Method) string {
+	server, client := method.Desc.IsStreamingServer(), method.Desc.IsStreamingClient()
+	switch {
+	case server && client:
+		return "bidi"
+	case server:
+		return "server"
+	case client:
+		return "client"
+	}
+	return ""
+}
+
+// streamKindLabel 返回流式类型的中文说明，用于提示信息及桩方法的错误信息
+func streamKindLabel(kind string) string {
+	switch kind {
+	case "server":
+		return "服务端流式"
+	case "client":
+		return "客户端流式"
+	}
+	return "双向流式"
+}
+
+// streamStub 返回 streaming=stub 时流式方法的方法体表达式：不发起请求，调用即 reject，
+// 避免以一元请求调用流式方法（响应只会得到第一条消息或直接失败）
+func streamStub(data ServiceInfo, method MethodInfo) string {
+	return "Promise.reject(new Error('" + data.FullName + "." + method.MethodName + " 为" + streamKindLabel(method.StreamKind) + "方法，不能以一元请求调用'))"
+}
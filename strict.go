@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"sort"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// strictField StrictXxx 类型中重新声明的字段
+type strictField struct {
+	Key        string // 字段键名
+	Type       string // 字段类型
+	Optional   bool   // 是否声明为可选（key?: T）
+	Deprecated bool   // proto 字段是否标记了 deprecated
+}
+
+// strictType strict_null 时为响应消息生成的 StrictXxx 类型：在 ts-proto 类型基础上，
+// 将单个消息字段、proto3 optional 字段及 oneof 成员重新声明为可选，嵌套消息递归使用对应的 StrictXxx
+type strictType struct {
+	Name     string        // 类型名，如 StrictOrder
+	Base     string        // ts-proto 类型名，如 Order
+	BaseFile string        // Base 所在的 proto 文件（用于导入）
+	Message  string        // proto 消息全名，如 shop.v1.Order
+	Fields   []strictField // 重新声明的字段
+}
+
+// strictName 返回消息对应的 strict 类型名
+func strictName(msg *protogen.Message) string {
+	return "Strict" + string(msg.Desc.Name())
+}
+
+// collectStrictTypes 收集方法响应（及其嵌套消息）中需要 StrictXxx 类型的消息，按名称排序
+// 不进入 google.protobuf 下的 well-known types，verb_response 为 void 的方法不计入
+func collectStrictTypes(methods []MethodInfo, verbResponses map[string]string, useJSON bool) []strictType {
+	needs := make(map[string]bool)    // 消息全名 -> 是否需要 strict 类型
+	visiting := make(map[string]bool) // 递归中的消息，循环引用时视为不需要
+	messages := make(map[string]*protogen.Message)
+
+	var need func(msg *protogen.Message) bool
+	need = func(msg *protogen.Message) bool {
+		name := string(msg.Desc.FullName())
+		if msg.Desc.ParentFile().Package() == "google.protobuf" || visiting[name] {
+			return false
+		}
+		if v, ok := needs[name]; ok {
+			return v
+		}
+		visiting[name] = true
+		result := false
+		for _, field := range msg.Fields {
+			switch {
+			case field.Desc.IsMap():
+				if value := field.Message.Fields[1]; value.Message != nil && need(value.Message) {
+					result = true
+				}
+			case field.Desc.IsList():
+				if field.Message != nil && need(field.Message) {
+					result = true
+				}
+			case field.Message != nil:
+				need(field.Message)
+				result = true
+			case field.Desc.HasOptionalKeyword() || field.Oneof != nil:
+				result = true
+			}
+		}
+		delete(visiting, name)
+		needs[name] = result
+		if result {
+			messages[name] = msg
+		}
+		return result
+	}
+	for _, m := range methods {
+		if verbResponses[m.HttpMethod] == "void" {
+			continue
+		}
+		if m.PageItem != nil {
+			need(m.PageItem)
+		} else {
+			need(m.Output)
+		}
+	}
+
+	var types []strictType
+	for _, msg := range messages {
+		t := strictType{
+			Name:     strictName(msg),
+			Base:     string(msg.Desc.Name()),
+			BaseFile: msg.Desc.ParentFile().Path(),
+			Message:  string(msg.Desc.FullName()),
+		}
+		for _, field := range msg.Fields {
+			if f, ok := strictFieldOf(field, t.Base, needs, useJSON); ok {
+				t.Fields = append(t.Fields, f)
+			}
+		}
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// strictFieldOf 返回字段在 strict 类型中的重新声明，不需要改写的字段返回 false
+func strictFieldOf(field *protogen.Field, base string, needs map[string]bool, useJSON bool) (strictField, bool) {
+	f, ok := strictFieldType(field, base, needs, useJSON)
+	f.Deprecated = isDeprecatedField(field)
+	return f, ok
+}
+
+// strictFieldType 返回字段在 strict 类型中的键名与类型
+func strictFieldType(field *protogen.Field, base string, needs map[string]bool, useJSON bool) (strictField, bool) {
+	key := fieldKey(field, useJSON)
+	baseType := base + "['" + key + "']"
+	switch {
+	case field.Desc.IsMap():
+		if value := field.Message.Fields[1]; value.Message != nil && needs[string(value.Message.Desc.FullName())] {
+			return strictField{Key: key, Type: "{ [key: string]: " + strictName(value.Message) + " }"}, true
+		}
+	case field.Desc.IsList():
+		if field.Message != nil && needs[string(field.Message.Desc.FullName())] {
+			return strictField{Key: key, Type: strictName(field.Message) + "[]"}, true
+		}
+	case field.Message != nil:
+		if needs[string(field.Message.Desc.FullName())] {
+			return strictField{Key: key, Type: strictName(field.Message), Optional: true}, true
+		}
+		return strictField{Key: key, Type: baseType, Optional: true}, true
+	case field.Desc.HasOptionalKeyword() || field.Oneof != nil:
+		return strictField{Key: key, Type: baseType, Optional: true}, true
+	}
+	return strictField{}, false
+}
+
+// isDeprecatedField 判断字段是否设置了 [deprecated = true]
+func isDeprecatedField(field *protogen.Field) bool {
+	options, ok := field.Desc.Options().(*descriptorpb.FieldOptions)
+	return ok && options.GetDeprecated()
+}
+
+// strictTypeNames 返回 ts-proto 类型名到 strict 类型名的映射
+func strictTypeNames(types []strictType) map[string]string {
+	names := make(map[string]string, len(types))
+	for _, t := range types {
+		names[t.Base] = t.Name
+	}
+	return names
+}
+
+// addStrictImports 将 strict 类型引用的 ts-proto 类型加入类型导入
+func addStrictImports(typeImports map[string][]string, types []strictType) {
+	for _, t := range types {
+		if importPath := protoFileToImportPath(t.BaseFile); importPath != "" {
+			typeImports[importPath] = uniqueAndSort(append(typeImports[importPath], t.Base))
+		}
+	}
+}
+
+// mergeStrictTypes 合并多个服务的 strict 类型，按 proto 消息全名去重、按名称排序；
+// 不同消息生成同名 strict 类型（如 shop.v1.Order 与 admin.v1.Order 都为 StrictOrder）时由 typeSources 报错，不在此静默取其一
+func mergeStrictTypes(services []*ServiceInfo) []strictType {
+	seen := make(map[string]bool)
+	var merged []strictType
+	for _, svc := range services {
+		for _, t := range svc.StrictTypes {
+			if !seen[t.Message] {
+				seen[t.Message] = true
+				merged = append(merged, t)
+			}
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return merged
+}
+
+// writeStrictTypes 写入 StrictXxx 类型定义：Omit 掉需要改写的字段后重新声明
+func writeStrictTypes(buf *bytes.Buffer, types []strictType) {
+	for _, t := range types {
+		keys := make([]string, len(t.Fields))
+		for i, f := range t.Fields {
+			keys[i] = f.Key
+		}
+		buf.WriteString("export type ")
+		buf.WriteString(t.Name)
+		buf.WriteString(" = Omit<")
+		buf.WriteString(t.Base)
+		buf.WriteString(", ")
+		buf.WriteString(quoteKeys(keys))
+		buf.WriteString("> & {\n")
+		for _, f := range t.Fields {
+			// 重新声明的字段不会继承 ts-proto 生成的 JSDoc，需要自行标注 deprecated
+			if f.Deprecated {
+				buf.WriteString("  /** @deprecated */\n")
+			}
+			buf.WriteString("  ")
+			buf.WriteString(exampleKey(f.Key))
+			if f.Optional {
+				buf.WriteString("?")
+			}
+			buf.WriteString(": ")
+			buf.WriteString(f.Type)
+			buf.WriteString(";\n")
+		}
+		buf.WriteString("};\n\n")
+	}
+}
+
+// strictResponseType 返回 strict_null 时替换后的类型名，没有对应 strict 类型时原样返回
+func strictResponseType(data ServiceInfo, typeName string) string {
+	if name, ok := data.StrictNames[typeName]; ok {
+		return name
+	}
+	return typeName
+}
+
+// strictNamesOf 返回 strict 类型名列表
+func strictNamesOf(types []strictType) []string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.Name
+	}
+	return names
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// optionalField 返回 proto3 optional 字段，oneofIndex 为其合成 oneof 在消息中的下标
+func optionalField(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, oneofIndex int32) *descriptorpb.FieldDescriptorProto {
+	field := protoField(name, number, kind, "")
+	field.Proto3Optional = proto.Bool(true)
+	field.OneofIndex = proto.Int32(oneofIndex)
+	return field
+}
+
+// customerFile 返回嵌套可选消息的订单服务：Order.customer（Customer）-> Customer.address（Address），
+// Order.note 为 proto3 optional，Order.items 为 repeated Customer
+func customerFile() *descriptorpb.FileDescriptorProto {
+	order := protoMessage("Order",
+		protoField("order_id", 1, typeString, ""),
+		protoField("customer", 2, typeMessage, ".shop.v1.Customer"),
+		optionalField("note", 3, typeString, 0),
+		repeatedField("buyers", 4, typeMessage, ".shop.v1.Customer"),
+	)
+	order.OneofDecl = []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_note")}}
+	return protoFile("shop/v1/order.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			order,
+			protoMessage("Customer",
+				protoField("name", 1, typeString, ""),
+				protoField("address", 2, typeMessage, ".shop.v1.Address"),
+			),
+			protoMessage("Address", protoField("city", 1, typeString, "")),
+			protoMessage("GetOrderReq", protoField("order_id", 1, typeString, "")),
+		},
+		protoService("OrderService",
+			protoMethod("GetOrder", ".shop.v1.GetOrderReq", ".shop.v1.Order", httpGet("/v1/orders/{order_id}")),
+		),
+	)
+}
+
+func TestStrictNullNestedOptionalMessages(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,strict_null=true", customerFile())
+	code := generatedFile(t, generated, "ts/orderApi.ts")
+	assertContains(t, code,
+		"import type { Customer, GetOrderReq, Order } from '@/api/proto-types/shop/v1/order';",
+		// 嵌套消息字段递归使用 StrictXxx，单个消息字段与 optional 字段为可选
+		"export type StrictOrder = Omit<Order, 'customer' | 'note' | 'buyers'> & {\n  customer?: StrictCustomer;\n  note?: Order['note'];\n  buyers: StrictCustomer[];\n};",
+		// Address 只有标量字段，不需要 StrictAddress，引用处沿用 ts-proto 类型并标为可选
+		"export type StrictCustomer = Omit<Customer, 'address'> & {\n  address?: Customer['address'];\n};",
+		"GetOrder: (data: GetOrderReq): Promise<StrictOrder> =>",
+	)
+	assertNotContains(t, code, "StrictAddress")
+}
+
+func TestStrictNullDisabled(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts", customerFile())
+	code := generatedFile(t, generated, "ts/orderApi.ts")
+	assertContains(t, code, "GetOrder: (data: GetOrderReq): Promise<Order> =>")
+	assertNotContains(t, code, "Strict")
+}
+
+func TestMergeStrictTypesByMessage(t *testing.T) {
+	shop := &ServiceInfo{StrictTypes: []strictType{{Name: "StrictOrder", Base: "Order", Message: "shop.v1.Order"}}}
+	again := &ServiceInfo{StrictTypes: []strictType{{Name: "StrictOrder", Base: "Order", Message: "shop.v1.Order"}}}
+	admin := &ServiceInfo{StrictTypes: []strictType{{Name: "StrictOrder", Base: "Order", Message: "admin.v1.Order"}}}
+
+	if merged := mergeStrictTypes([]*ServiceInfo{shop, again}); len(merged) != 1 {
+		t.Errorf("同一消息的 strict 类型应只保留一个，实际为 %d 个", len(merged))
+	}
+	if merged := mergeStrictTypes([]*ServiceInfo{shop, admin}); len(merged) != 2 {
+		t.Errorf("不同消息的 strict 类型不应按名称合并，实际为 %d 个", len(merged))
+	}
+	err := flatTypeConflicts("flatApi.ts", []*ServiceInfo{shop, admin})
+	assertErrorContains(t, err, "flatApi.ts 中的类型 StrictOrder 同时来自 shop.v1.Order 与 admin.v1.Order")
+}
+
+func TestFlattenStrictNullRejectsDuplicateMessages(t *testing.T) {
+	_, err := runPlugin("output_paths=ts,flatten=true,strict_null=true",
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"),
+		orderFile("admin/v1/order.proto", "admin.v1", "AdminOrderService", "admin"))
+	assertErrorContains(t, err, "flatApi.ts 中的类型 StrictOrder 同时来自 shop.v1.Order 与 admin.v1.Order")
+}
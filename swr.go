@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// writeSWRImport hooks=swr 时写入 swr 的导入：GET 方法使用 useSWR，其余方法使用 swr/mutation 的 useSWRMutation
+func writeSWRImport(buf *bytes.Buffer, data ServiceInfo) {
+	if data.Hooks != "swr" {
+		return
+	}
+	hasQuery, hasMutation := false, false
+	for _, method := range data.Methods {
+		if method.HttpMethod == "get" {
+			hasQuery = true
+		} else {
+			hasMutation = true
+		}
+	}
+	if hasQuery {
+		buf.WriteString("import useSWR from 'swr';\n")
+	}
+	if hasMutation {
+		buf.WriteString("import useSWRMutation from 'swr/mutation';\n")
+	}
+}
+
+// swrKey 返回方法的 SWR 缓存键前缀：路径模板（如 '/v1/orders/{order_id}'），GET 方法按路径区分
+func swrKey(data ServiceInfo, method MethodInfo) string {
+	return "'" + strings.ReplaceAll(methodPath(data, method), "'", "\\'") + "'"
+}
+
+// writeSWRHooks hooks=swr 时为每个方法生成 hook（SWR 2）：
+// GET 方法生成 useSWR（key 为 [路径模板, data]，fetcher 调用 API 对象的方法），其余方法生成 useSWRMutation（trigger 的参数为请求数据）；
+// 没有请求参数的方法 key 只有路径模板，fetcher 不传参数
+// typed 为 true 时生成 TS 类型标注，indent 为每层缩进
+func writeSWRHooks(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if data.Hooks != "swr" {
+		return
+	}
+	for _, method := range data.Methods {
+		buf.WriteString("export const ")
+		buf.WriteString(queryHookName(method))
+		if method.HttpMethod == "get" {
+			param, arg, key := "data", "data", "["+swrKey(data, method)+", data]"
+			if typed {
+				param = "data: " + requestParamType(data, method)
+			}
+			if method.NoRequest {
+				param, arg, key = "", "", swrKey(data, method)
+			}
+			buf.WriteString(" = (" + param + ") =>\n")
+			buf.WriteString(indent + "useSWR(" + key + ", () => " + data.ApiFileName + "." + method.MethodName + "(" + arg + ")" + unwrapErrorTuple(data, method, typed, indent, indent) + ");\n\n")
+			continue
+		}
+		fetcher := "(_key, { arg })"
+		if typed {
+			fetcher = "(_key: string, { arg }: { arg: " + requestParamType(data, method) + " })"
+		}
+		arg := "arg"
+		if method.NoRequest {
+			fetcher, arg = "()", ""
+		}
+		buf.WriteString(" = () =>\n")
+		buf.WriteString(indent + "useSWRMutation(" + swrKey(data, method) + ", " + fetcher + " => " + data.ApiFileName + "." + method.MethodName + "(" + arg + ")" + unwrapErrorTuple(data, method, typed, indent, indent) + ");\n\n")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// eventFile 返回请求含两个 Timestamp 字段的事件服务，end_time 的行尾注释为 @example 2024-05-01T08:00:00Z
+func eventFile() *descriptorpb.FileDescriptorProto {
+	return withComments(protoFile("ops/v1/event.proto", "ops.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("CreateEventReq",
+				protoField("name", 1, typeString, ""),
+				protoField("start_time", 2, typeMessage, ".google.protobuf.Timestamp"),
+				protoField("end_time", 3, typeMessage, ".google.protobuf.Timestamp"),
+			),
+		},
+		protoService("EventService",
+			protoMethod("CreateEvent", ".ops.v1.CreateEventReq", ".google.protobuf.Empty", httpPost("/v1/events", "*")),
+		),
+	), []int32{4, 0, 2, 2}, "", " @example 2024-05-01T08:00:00Z\n")
+}
+
+func TestTimestampTypeInZodAndExamples(t *testing.T) {
+	tests := []struct {
+		param   string
+		zod     string
+		start   string
+		example string
+	}{
+		{"", "z.string()", "'1970-01-01T00:00:00Z'", "'2024-05-01T08:00:00Z'"},
+		{",timestamp_type=string", "z.string()", "'1970-01-01T00:00:00Z'", "'2024-05-01T08:00:00Z'"},
+		{",timestamp_type=Date", "z.date()", "new Date(0)", "new Date('2024-05-01T08:00:00Z')"},
+	}
+	for _, tt := range tests {
+		generated := mustRunPlugin(t, "output_paths=ts,emit_zod=true,emit_examples=true"+tt.param, eventFile())
+		code := generatedFile(t, generated, "ts/eventApi.ts")
+		assertContains(t, code,
+			"  startTime: "+tt.zod+".optional(),",
+			"      startTime: "+tt.start+",",
+			"      endTime: "+tt.example+",",
+		)
+	}
+}
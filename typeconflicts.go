@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// typeSources 汇总文件（flatten、merge_by_package、api.d.ts、index.ts）中的类型名 -> 来源，
+// 来源为导入模块、proto 消息全名或声明该类型的服务文件；同名类型来自不同来源时汇总文件会重复声明或导出错误的类型
+type typeSources struct {
+	file    string            // 汇总文件名，用于错误信息
+	sources map[string]string // 类型名 -> 来源
+	errs    []string
+}
+
+// newTypeSources 创建汇总文件 file 的类型来源登记
+func newTypeSources(file string) *typeSources {
+	return &typeSources{file: file, sources: make(map[string]string)}
+}
+
+// add 登记类型名 name 来自 source，已来自其他来源时记录冲突
+func (s *typeSources) add(name, source string) {
+	prev, ok := s.sources[name]
+	if !ok {
+		s.sources[name] = source
+		return
+	}
+	if prev != source {
+		s.errs = append(s.errs, fmt.Sprintf("%s 中的类型 %s 同时来自 %s 与 %s", s.file, name, prev, source))
+	}
+}
+
+// addImports 登记服务从 ts-proto（或 emit_interfaces 的 types.ts）导入的类型，来源为完整的导入模块
+func (s *typeSources) addImports(svc *ServiceInfo) {
+	for importPath, names := range svc.TypeImports {
+		module := svc.TypesImportPath
+		if !strings.HasSuffix(module, "/") && importPath != "" {
+			module += "/"
+		}
+		module += importPath
+		for _, name := range names {
+			s.add(name, module)
+		}
+	}
+}
+
+// addStrictTypes 登记服务的 StrictXxx 类型，来源为 proto 消息全名
+func (s *typeSources) addStrictTypes(svc *ServiceInfo) {
+	for _, t := range svc.StrictTypes {
+		s.add(t.Name, t.Message)
+	}
+}
+
+// addPage 登记服务文件中的 Page<T>，来源为其列表与 token 的键名（键名相同的 Page<T> 为同一类型）
+func (s *typeSources) addPage(svc *ServiceInfo) {
+	if itemsKey, tokenKey, ok := pageKeys(*svc); ok {
+		s.add("Page", "Page<T>（"+itemsKey+"、"+tokenKey+"）")
+	}
+}
+
+// err 返回登记过程中发现的全部冲突，没有冲突时返回 nil
+func (s *typeSources) err() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("汇总文件中存在同名类型，请重命名其中的 proto 消息，或改为按服务生成文件:\n  %s", strings.Join(s.errs, "\n  "))
+}
+
+// flatTypeConflicts 检查 flatten / merge_by_package 汇总文件 file 中各服务导入的类型及 StrictXxx 类型是否重名：
+// 不同 proto 包的同名消息（如 shop.v1.Order 与 admin.v1.Order）会被导入两次，TS 编译失败
+func flatTypeConflicts(file string, services []*ServiceInfo) error {
+	sources := newTypeSources(file)
+	for _, svc := range services {
+		sources.addImports(svc)
+		sources.addStrictTypes(svc)
+	}
+	return sources.err()
+}
+
+// bundleTypeConflicts 检查 bundle_dts 的 api.d.ts 中导入的类型、StrictXxx 类型及声明的 XxxApi 接口是否重名：
+// 除同名消息外，package_dirs 时不同包下的同名服务会声明相同的 XxxApi 接口及 Api 中相同的键
+func bundleTypeConflicts(services []*ServiceInfo) error {
+	sources := newTypeSources("api.d.ts")
+	sources.add("Api", "api.d.ts 的汇总接口")
+	for _, svc := range services {
+		sources.addImports(svc)
+		sources.addStrictTypes(svc)
+		sources.add(toPascalCase(svc.ApiFileName), "服务 "+svc.FullName)
+	}
+	return sources.err()
+}
+
+// indexTypeConflicts 检查 generate_index 的 index.ts 以 export type 重新导出的类型是否重名：
+// 导入的 ts-proto 类型、各服务文件生成的 XxxQuery / XxxResult / XxxOps（来源为服务文件）、Page 及 StrictXxx，
+// 同名时汇总文件会重复导出，或只导出其中一个服务的类型
+func indexTypeConflicts(file string, services []*ServiceInfo) error {
+	sources := newTypeSources(file)
+	for _, svc := range sortedByFileName(services) {
+		sources.addImports(svc)
+		sources.addStrictTypes(svc)
+		sources.addPage(svc)
+		for _, name := range generatedTypeNames(*svc) {
+			sources.add(name, "./"+serviceFilePath(*svc))
+		}
+	}
+	return sources.err()
+}
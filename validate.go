@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// validateService 校验服务中带 google.api.http 注解的方法，返回问题描述列表
+// 未加注解的方法不参与生成，也不视为问题
+func validateService(service *protogen.Service, config *PluginConfig) []string {
+	var problems []string
+	for _, method := range service.Methods {
+		if httpRuleOf(method) == nil {
+			continue
+		}
+		name := method.Desc.FullName()
+		httpRule := extractHttpRule(method, config.DefaultVerb)
+		if httpRule == nil {
+			problems = append(problems, fmt.Sprintf("%s: 无法识别的 HTTP 规则（空路径或 kind 无效的 custom），该方法不会生成", name))
+			continue
+		}
+		problems = append(problems, validateRulePath(method, httpRule.Path)...)
+		for _, binding := range extractAdditionalBindings(method, config.DefaultVerb) {
+			problems = append(problems, validateRulePath(method, binding.Path)...)
+		}
+	}
+	return problems
+}
+
+// validateRulePath 校验方法某条 HTTP 规则的路径模板及其中的路径变量
+func validateRulePath(method *protogen.Method, path string) []string {
+	name := method.Desc.FullName()
+	if err := validatePathTemplate(path); err != nil {
+		return []string{fmt.Sprintf("%s: 路径 %q 不合法: %v", name, path, err)}
+	}
+	var problems []string
+	_, vars := parsePathTemplate(path)
+	for _, v := range vars {
+		if !hasFieldPath(method.Input, v.FieldPath) {
+			problems = append(problems, fmt.Sprintf("%s: 路径变量 %s 在请求消息 %s 中不存在", name, v.FieldPath, method.Input.Desc.FullName()))
+		}
+	}
+	return problems
+}
+
+// validatePathTemplate 校验 HTTP 路径模板：以 / 开头，花括号成对且不嵌套，变量名只含字母、数字、_ 和 .
+func validatePathTemplate(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("必须以 / 开头")
+	}
+	depth := 0
+	var name strings.Builder
+	for _, r := range path {
+		switch {
+		case r == '{':
+			if depth > 0 {
+				return fmt.Errorf("花括号不能嵌套")
+			}
+			depth++
+			name.Reset()
+		case r == '}':
+			if depth == 0 {
+				return fmt.Errorf("多余的 }")
+			}
+			depth--
+			fieldPath := strings.SplitN(name.String(), "=", 2)[0]
+			if fieldPath == "" {
+				return fmt.Errorf("变量名为空")
+			}
+			for _, c := range fieldPath {
+				if !(c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+					return fmt.Errorf("变量名 %q 含非法字符", fieldPath)
+				}
+			}
+		case depth > 0:
+			name.WriteRune(r)
+		}
+	}
+	if depth > 0 {
+		return fmt.Errorf("缺少 }")
+	}
+	return nil
+}
+
+// hasFieldPath 判断以 proto 字段名表示的字段路径（如 book.id）是否存在于消息中
+func hasFieldPath(msg *protogen.Message, fieldPath string) bool {
+	for _, name := range strings.Split(fieldPath, ".") {
+		if msg == nil {
+			return false
+		}
+		var next *protogen.Message
+		found := false
+		for _, field := range msg.Fields {
+			if string(field.Desc.Name()) == name {
+				found, next = true, field.Message
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+		msg = next
+	}
+	return true
+}
+
+// serviceImportExts 解析相对导入时尝试的扩展名（与常见打包工具一致）
+var serviceImportExts = []string{"", ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", "/index.ts", "/index.js"}
+
+// serviceImportOverrides 返回本次生成的各服务在注释中以 @frontend:service_import 指定的导入（服务全名 -> 导入路径），
+// 没有指定的服务值为空串；flatten、merge_by_package 时指令不生效，全部为空串
+func serviceImportOverrides(gen *protogen.Plugin, config *PluginConfig) map[string]string {
+	overrides := make(map[string]string)
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		for _, service := range f.Services {
+			if !config.ServiceFilter.allows(string(service.Desc.Name())) {
+				continue
+			}
+			override := ""
+			if !config.Flatten && !config.MergeByPackage {
+				override, _ = parseServiceImportDirective(getServiceComment(f, service, config.DeepComments))
+			}
+			overrides[string(service.Desc.FullName())] = override
+		}
+	}
+	return overrides
+}
+
+// verifyServiceImports 校验各输出目录中服务实际使用的 service 导入能否解析到文件：
+// 服务注释中有 @frontend:service_import 时校验该导入，否则校验输出路径对应的配置
+// 只校验 ./ 或 ../ 开头的相对路径；包名、别名（如 @/api/api）等无法在插件中解析，跳过
+func verifyServiceImports(gen *protogen.Plugin, config *PluginConfig) error {
+	var missing []string
+	check := func(outputPath, spec, service string) {
+		if !strings.HasPrefix(spec, "./") && !strings.HasPrefix(spec, "../") {
+			return
+		}
+		base := filepath.Join(outputPath, filepath.FromSlash(spec))
+		for _, ext := range serviceImportExts {
+			if info, err := os.Stat(base + filepath.FromSlash(ext)); err == nil && !info.IsDir() {
+				return
+			}
+		}
+		if service != "" {
+			missing = append(missing, fmt.Sprintf("%s: %s（%s 的 %s）", outputPath, spec, service, serviceImportDirective))
+			return
+		}
+		missing = append(missing, fmt.Sprintf("%s: %s", outputPath, spec))
+	}
+	overrides := serviceImportOverrides(gen, config)
+	services := make([]string, 0, len(overrides))
+	for service := range overrides {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	// 每个输出目录中：使用配置的服务共用一次校验，指定了导入的服务各自校验
+	verify := func(outputPath, configured string) {
+		usesConfigured := false
+		for _, service := range services {
+			if override := overrides[service]; override != "" {
+				check(outputPath, override, service)
+			} else {
+				usesConfigured = true
+			}
+		}
+		if usesConfigured {
+			check(outputPath, configured, "")
+		}
+	}
+	for _, outputPath := range config.OutputPaths {
+		verify(outputPath.Path, serviceImportFor(outputPath, config))
+	}
+	for _, outputPath := range config.OutputPathsJS {
+		verify(outputPath.Path, serviceImportForJS(outputPath, config))
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("service 导入无法解析（输出目录: 导入路径）:\n  %s", strings.Join(missing, "\n  "))
+	}
+	return nil
+}
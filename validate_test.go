@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyServiceImportWithOverride(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "api")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeHelper := func(name string) {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("export default {};\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeHelper("request.ts")
+	// OrderService 以 @frontend:service_import 改用 ../adminRequest，GoodsService 使用配置的 ../request
+	order := withComments(orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"), []int32{6, 0}, " 订单\n @frontend:service_import ../adminRequest\n", "")
+	goods := orderFile("shop/v2/goods.proto", "shop.v2", "GoodsService", "goods")
+	param := "output_paths=" + dir + ",verify_service_import=true,service_import="
+
+	_, err := execPlugin(param+"../request", order, goods)
+	assertErrorContains(t, err, dir+": ../adminRequest（shop.v1.OrderService 的 @frontend:service_import）")
+
+	writeHelper("adminRequest.ts")
+	if _, err := execPlugin(param+"../request", order, goods); err != nil {
+		t.Fatalf("指定的导入存在时应通过校验: %v", err)
+	}
+
+	// 只有指定了导入的服务时，配置的 service_import 不被使用，不校验
+	if _, err := execPlugin(param+"../missing", order); err != nil {
+		t.Fatalf("配置的 service_import 未被使用时不应校验: %v", err)
+	}
+	_, err = execPlugin(param+"../missing", order, goods)
+	assertErrorContains(t, err, dir+": ../missing")
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// validateFramework 校验 framework 的取值
+func validateFramework(value string) error {
+	if value != "vue" {
+		return fmt.Errorf("framework 只支持 vue: %s", value)
+	}
+	return nil
+}
+
+// writeVueHeader framework=vue 时在文件头部说明生成的组合式函数的形态
+func writeVueHeader(buf *bytes.Buffer, data ServiceInfo) {
+	if data.Framework != "vue" {
+		return
+	}
+	buf.WriteString("// Vue 组合式函数：每个方法生成 useXxx，返回 { data, loading, error, execute }（均为 ref，execute 为函数）\n")
+	buf.WriteString("// - GET 方法 useXxx(req) 创建时立即以 req 发起请求，之后可调用 execute(req) 以新的参数重新请求\n")
+	buf.WriteString("// - 其余方法 useXxx() 不自动请求，调用 execute(req) 时才发起\n")
+	buf.WriteString("// - execute 返回响应（失败时为 null），data 为最近一次成功的响应，error 为最近一次请求的异常（成功时为 null）\n\n")
+}
+
+// writeVueImport framework=vue 时写入 vue 的导入
+func writeVueImport(buf *bytes.Buffer, data ServiceInfo) {
+	if data.Framework != "vue" {
+		return
+	}
+	buf.WriteString("import { ref, shallowRef } from 'vue';\n")
+}
+
+// writeComposables framework=vue 时生成模块内的 useRequest 辅助函数，并为每个方法生成组合式函数（名称同 queryHookName）：
+// GET 方法创建时立即请求，其余方法只暴露 execute；error_tuple 时解开元组，出错写入 error
+// typed 为 true 时生成 TS 类型标注，indent 为每层缩进
+func writeComposables(buf *bytes.Buffer, data ServiceInfo, typed bool, indent string) {
+	if data.Framework != "vue" {
+		return
+	}
+	in1, in2, in3 := indent, indent+indent, indent+indent+indent
+	if typed {
+		buf.WriteString("const useRequest = <Args extends unknown[], T>(call: (...args: Args) => Promise<T>, immediate?: Args) => {\n")
+		buf.WriteString(in1 + "const data = shallowRef<T | null>(null);\n")
+		buf.WriteString(in1 + "const loading = ref(false);\n")
+		buf.WriteString(in1 + "const error = ref<Error | null>(null);\n")
+		buf.WriteString(in1 + "const execute = async (...args: Args): Promise<T | null> => {\n")
+	} else {
+		buf.WriteString("const useRequest = (call, immediate) => {\n")
+		buf.WriteString(in1 + "const data = shallowRef(null);\n")
+		buf.WriteString(in1 + "const loading = ref(false);\n")
+		buf.WriteString(in1 + "const error = ref(null);\n")
+		buf.WriteString(in1 + "const execute = async (...args) => {\n")
+	}
+	buf.WriteString(in2 + "loading.value = true;\n")
+	buf.WriteString(in2 + "error.value = null;\n")
+	buf.WriteString(in2 + "try {\n")
+	buf.WriteString(in3 + "data.value = await call(...args);\n")
+	buf.WriteString(in3 + "return data.value;\n")
+	buf.WriteString(in2 + "} catch (err) {\n")
+	if typed {
+		buf.WriteString(in3 + "error.value = err as Error;\n")
+	} else {
+		buf.WriteString(in3 + "error.value = err;\n")
+	}
+	buf.WriteString(in3 + "return null;\n")
+	buf.WriteString(in2 + "} finally {\n")
+	buf.WriteString(in3 + "loading.value = false;\n")
+	buf.WriteString(in2 + "}\n")
+	buf.WriteString(in1 + "};\n")
+	buf.WriteString(in1 + "if (immediate) {\n")
+	buf.WriteString(in2 + "void execute(...immediate);\n")
+	buf.WriteString(in1 + "}\n")
+	buf.WriteString(in1 + "return { data, loading, error, execute };\n")
+	buf.WriteString("};\n\n")
+
+	for _, method := range data.Methods {
+		// 组合式函数的参数为 req，传给 API 方法的参数为 data，避免同名遮蔽
+		param, callParam, arg, immediate := "req", "data", "data", "req"
+		if typed {
+			param = "req: " + requestParamType(data, method)
+			callParam = "data: " + requestParamType(data, method)
+		}
+		if method.NoRequest {
+			param, callParam, arg, immediate = "", "", "", ""
+		}
+		call := "(" + callParam + ") => " + data.ApiFileName + "." + method.MethodName + "(" + arg + ")" + unwrapErrorTuple(data, method, typed, in1, indent)
+		buf.WriteString("export const ")
+		buf.WriteString(queryHookName(method))
+		if method.HttpMethod == "get" {
+			buf.WriteString(" = (" + param + ") =>\n")
+			buf.WriteString(in1 + "useRequest(" + call + ", [" + immediate + "]);\n\n")
+		} else {
+			buf.WriteString(" = () =>\n")
+			buf.WriteString(in1 + "useRequest(" + call + ");\n\n")
+		}
+	}
+}
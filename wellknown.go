@@ -0,0 +1,39 @@
+package main
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// jsonWellKnownTypes google.protobuf 中 JSON 表示为任意 JSON 值的类型及其 TS 类型
+// ts-proto 为这些消息导出的接口是包装结构（如 Struct 的 { fields }），与 HTTP 上传输的 JSON 不一致，
+// 作为方法的请求/响应时直接使用对应的 TS 类型，不导入 ts-proto 的接口；作为字段时 ts-proto 已映射为普通 JSON 值
+var jsonWellKnownTypes = map[string]string{
+	"Struct":    "Record<string, any>",
+	"Value":     "any",
+	"ListValue": "any[]",
+}
+
+// isEmptyMessage 判断请求消息是否没有字段（如 google.protobuf.Empty），此时方法没有需要发送的数据
+func isEmptyMessage(msg *protogen.Message) bool {
+	return msg.Desc.Fields().Len() == 0
+}
+
+// isJSONWellKnown 判断消息是否为 Struct / Value / ListValue
+func isJSONWellKnown(msg *protogen.Message) bool {
+	if msg.Desc.ParentFile().Package() != "google.protobuf" {
+		return false
+	}
+	_, ok := jsonWellKnownTypes[string(msg.Desc.Name())]
+	return ok
+}
+
+// messageTSType 返回方法请求/响应消息在生成代码中的 TS 类型：Struct / Value / ListValue 为对应的 JSON 类型，其余为消息名
+func messageTSType(msg *protogen.Message) string {
+	if isJSONWellKnown(msg) {
+		return jsonWellKnownTypes[string(msg.Desc.Name())]
+	}
+	return string(msg.Desc.Name())
+}
+
+// isWellKnown 判断消息是否为名为 name 的 google.protobuf well-known type（如 Timestamp），msg 为 nil 时返回 false
+func isWellKnown(msg *protogen.Message, name string) bool {
+	return msg != nil && msg.Desc.ParentFile().Package() == "google.protobuf" && string(msg.Desc.Name()) == name
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// structFile 返回请求消息含 Struct / Value / ListValue 字段的服务，以及直接以 Struct 为请求、Value 为响应的 Echo
+func structFile() *descriptorpb.FileDescriptorProto {
+	return protoFile("shop/v1/event.proto", "shop.v1",
+		[]*descriptorpb.DescriptorProto{
+			protoMessage("TrackReq",
+				protoField("name", 1, typeString, ""),
+				protoField("attrs", 2, typeMessage, ".google.protobuf.Struct"),
+				protoField("payload", 3, typeMessage, ".google.protobuf.Value"),
+				protoField("tags", 4, typeMessage, ".google.protobuf.ListValue"),
+			),
+		},
+		protoService("EventService",
+			protoMethod("Track", ".shop.v1.TrackReq", ".google.protobuf.Empty", httpPost("/v1/events", "*")),
+			protoMethod("TrackAttrs", ".shop.v1.TrackReq", ".google.protobuf.Empty", httpPost("/v1/events/{name}/attrs", "attrs")),
+			protoMethod("Echo", ".google.protobuf.Struct", ".google.protobuf.Value", httpPost("/v1/echo", "*")),
+		),
+	)
+}
+
+func TestStructFieldsInInterfaces(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,emit_interfaces=true", structFile())
+	assertContains(t, generatedFile(t, generated, "ts/types.ts"),
+		"export interface TrackReq {\n  name: string;\n  attrs?: { [key: string]: any };\n  payload?: any;\n  tags?: any[];\n}",
+	)
+	assertNotContains(t, generatedFile(t, generated, "ts/types.ts"), "interface Struct", "interface Value", "interface ListValue")
+}
+
+func TestStructPassedThroughInBody(t *testing.T) {
+	generated := mustRunPlugin(t, "output_paths=ts,output_paths_js=js", structFile())
+	code := generatedFile(t, generated, "ts/eventApi.ts")
+	assertContains(t, code,
+		"Track: (data: TrackReq): Promise<Empty> =>\n    service.post('/v1/events', data)",
+		// body 为 Struct 字段时原样发送该字段
+		"service.post(`/v1/events/${encodeURIComponent(data.name)}/attrs`, data.attrs",
+		// Struct 请求与 Value 响应直接映射为 JSON 类型，不从 ts-proto 导入
+		"Echo: (data: Record<string, any>): Promise<any> =>\n    service.post('/v1/echo', data)",
+	)
+	assertNotContains(t, code, "Struct", "ListValue")
+	assertContains(t, generatedFile(t, generated, "js/eventApi.js"), "Echo: (data) => service.post('/v1/echo', data)")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"archive/zip"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// zipEntries 以 param 生成 zip 并返回其中的条目名
+func zipEntries(t *testing.T, param string) []string {
+	t.Helper()
+	archive := filepath.Join(t.TempDir(), "api.zip")
+	if _, err := execPlugin(param+",output_zip="+archive, orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop")); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestZipEntriesStayInsideArchive(t *testing.T) {
+	abs := filepath.Join(t.TempDir(), "abs", "out")
+	tests := []struct {
+		param string
+		want  []string
+	}{
+		{"output_paths=ts", []string{"ts/orderApi.ts"}},
+		// 相对路径开头的 ../ 去掉，解压时不会写到解压目录之外
+		{"output_paths=../web/src/api", []string{"web/src/api/orderApi.ts"}},
+		// 绝对路径只保留最后一级目录名
+		{"output_paths=" + abs, []string{"out/orderApi.ts"}},
+		{"output_paths=.,output_paths_js=" + abs, []string{"orderApi.ts", "out/orderApi.js"}},
+	}
+	for _, tt := range tests {
+		if got := zipEntries(t, tt.param); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: zip 条目 = %v, want %v", tt.param, got, tt.want)
+		}
+	}
+}
+
+func TestZipEntryRootConflict(t *testing.T) {
+	_, err := execPlugin("output_paths=ts,output_paths_js=../ts,output_zip="+filepath.Join(t.TempDir(), "api.zip"),
+		orderFile("shop/v1/order.proto", "shop.v1", "OrderService", "shop"))
+	assertErrorContains(t, err, "输出目录 ts 与 ../ts 在 zip 中都对应 ts")
+}
+
+func TestZipEntryRejectsEscapingNames(t *testing.T) {
+	out := newOutputWriter("api.zip")
+	if err := out.write("ts", "../../orderApi.ts", []byte("export {};")); err == nil {
+		t.Error("zip 条目位于解压目录之外时应报错")
+	}
+}
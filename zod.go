@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// zodSchemaName 返回方法请求校验 schema 的常量名（例如：CreateOrder -> createOrderSchema）
+func zodSchemaName(method MethodInfo) string {
+	return toCamelCase(method.MethodName) + "Schema"
+}
+
+// zodObject 将消息渲染为 z.object({...}) 表达式，字段键与 ts-proto 一致，嵌套消息递归展开（循环引用处为 z.any()）
+// indent 为当前行缩进，step 为每层缩进，trailingComma 为 trailing_comma 配置
+func zodObject(msg *protogen.Message, useJSON bool, timestampType string, indent, step, trailingComma string, seen map[string]bool) string {
+	if seen[string(msg.Desc.FullName())] {
+		return "z.any()"
+	}
+	seen[string(msg.Desc.FullName())] = true
+	defer delete(seen, string(msg.Desc.FullName()))
+
+	if len(msg.Fields) == 0 {
+		return "z.object({})"
+	}
+	var b strings.Builder
+	b.WriteString("z.object({\n")
+	for i, field := range msg.Fields {
+		b.WriteString(indent + step)
+		b.WriteString(exampleKey(fieldKey(field, useJSON)))
+		b.WriteString(": ")
+		b.WriteString(zodField(field, useJSON, timestampType, indent+step, step, trailingComma, seen))
+		b.WriteString(memberEnd(i == len(msg.Fields)-1, trailingComma))
+	}
+	b.WriteString(indent + "})")
+	return b.String()
+}
+
+// zodField 返回字段的 zod 类型：repeated 为 z.array，map 为 z.record；
+// 消息字段、proto3 optional 及 oneof 成员在 ts-proto 中可为 undefined，追加 .optional()
+func zodField(field *protogen.Field, useJSON bool, timestampType string, indent, step, trailingComma string, seen map[string]bool) string {
+	switch {
+	case field.Desc.IsMap():
+		return "z.record(z.string(), " + zodSingular(field.Message.Fields[1], useJSON, timestampType, indent, step, trailingComma, seen) + ")"
+	case field.Desc.IsList():
+		return "z.array(" + zodSingular(field, useJSON, timestampType, indent, step, trailingComma, seen) + ")"
+	}
+	schema := zodSingular(field, useJSON, timestampType, indent, step, trailingComma, seen)
+	if field.Message != nil || field.Desc.HasOptionalKeyword() || field.Oneof != nil {
+		schema += ".optional()"
+	}
+	return schema
+}
+
+// zodSingular 返回单个值的 zod 类型（与 ts-proto 默认映射一致：64 位整数为 number，bytes 为 Uint8Array，枚举为数值）
+func zodSingular(field *protogen.Field, useJSON bool, timestampType string, indent, step, trailingComma string, seen map[string]bool) string {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "z.boolean()"
+	case protoreflect.StringKind:
+		return "z.string()"
+	case protoreflect.BytesKind:
+		return "z.instanceof(Uint8Array)"
+	case protoreflect.EnumKind:
+		return "z.number().int()"
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "z.number()"
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return zodMessage(field.Message, useJSON, timestampType, indent, step, trailingComma, seen)
+	}
+	return "z.number().int()"
+}
+
+// zodMessage 返回消息类型的 zod 类型，well-known types 与 emit_interfaces 生成的类型一致：
+// Timestamp 按 timestamp_type 为 z.string()（ISO 字符串）或 z.date()，Duration 为 z.string()，其余按 ts-proto 的映射处理
+func zodMessage(msg *protogen.Message, useJSON bool, timestampType string, indent, step, trailingComma string, seen map[string]bool) string {
+	if msg.Desc.ParentFile().Package() == "google.protobuf" {
+		switch msg.Desc.Name() {
+		case "Timestamp":
+			if timestampType == "Date" {
+				return "z.date()"
+			}
+			return "z.string()"
+		case "Duration":
+			return "z.string()"
+		case "Struct":
+			return "z.record(z.string(), z.any())"
+		case "ListValue":
+			return "z.array(z.any())"
+		case "Value", "Any":
+			return "z.any()"
+		case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value", "UInt32Value":
+			return "z.number()"
+		case "BoolValue":
+			return "z.boolean()"
+		case "StringValue":
+			return "z.string()"
+		case "BytesValue":
+			return "z.instanceof(Uint8Array)"
+		case "FieldMask":
+			return "z.array(z.string())"
+		case "Empty":
+			return "z.object({})"
+		}
+		return "z.any()"
+	}
+	return zodObject(msg, useJSON, timestampType, indent, step, trailingComma, seen)
+}
+
+// writeZodImport 开启 emit_zod 时写入 zod 的 import
+func writeZodImport(buf *bytes.Buffer, data ServiceInfo) {
+	if !data.EmitZod {
+		return
+	}
+	buf.WriteString("import { z } from 'zod';\n")
+}
+
+// writeZodSchemas 为每个方法的请求消息生成 zod schema 常量（如 createOrderSchema），indent 为每层缩进
+func writeZodSchemas(buf *bytes.Buffer, data ServiceInfo, indent string) {
+	if !data.EmitZod {
+		return
+	}
+	for _, method := range data.Methods {
+		buf.WriteString("export const ")
+		buf.WriteString(zodSchemaName(method))
+		buf.WriteString(" = ")
+		if isJSONWellKnown(method.Input) {
+			buf.WriteString(zodMessage(method.Input, data.UseJSONNames, data.TimestampType, "", indent, data.TrailingComma, map[string]bool{}))
+		} else {
+			buf.WriteString(zodObject(method.Input, data.UseJSONNames, data.TimestampType, "", indent, data.TrailingComma, map[string]bool{}))
+		}
+		buf.WriteString(";\n\n")
+	}
+}